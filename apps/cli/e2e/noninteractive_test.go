@@ -49,7 +49,7 @@ func TestNewCommand_GeneratesProjectAndPassesSmoke(t *testing.T) {
 	projectName := "demo"
 	projectDir := filepath.Join(base, projectName)
 
-	result := runCLI(t, baseEnv(t), "new", projectName, base, "--module", "github.com/acme/demo", "--no-git")
+	result := runCLI(t, baseEnv(t), "new", projectName, base, "--module", "github.com/acme/demo", "--no-git", "--license", "Apache-2.0", "--author", "Acme Inc.")
 	if result.Err != nil {
 		t.Fatalf("expected scaffold to succeed: %v\n%s", result.Err, result.Output)
 	}
@@ -63,6 +63,9 @@ func TestNewCommand_GeneratesProjectAndPassesSmoke(t *testing.T) {
 
 	assertFileContains(t, filepath.Join(projectDir, "README.md"), "demo/")
 	assertFileContains(t, filepath.Join(projectDir, "package.json"), `"web:test"`)
+	assertFileContains(t, filepath.Join(projectDir, "LICENSE"), "Apache License")
+	assertFileContains(t, filepath.Join(projectDir, "package.json"), `"author": "Acme Inc."`)
+	assertFileContains(t, filepath.Join(projectDir, "apps/api/Dockerfile"), `org.opencontainers.image.licenses="Apache-2.0"`)
 
 	smokeEnv := baseEnv(t)
 	runCommand(t, projectDir, smokeEnv, "bun", "install")
@@ -158,6 +161,38 @@ func TestNewCommand_FailsForIncompleteS3Configuration(t *testing.T) {
 	}
 }
 
+func TestNewCommand_FailsFastWithoutTerminal(t *testing.T) {
+	result := runCLI(t, baseEnv(t), "new")
+	if result.Err == nil {
+		t.Fatalf("expected the wizard to be refused without a terminal")
+	}
+	for _, want := range []string{"needs a terminal", "GOKICKSTART_NAME", "GOKICKSTART_MODULE"} {
+		if !strings.Contains(result.Output, want) {
+			t.Fatalf("expected %q in the error, got:\n%s", want, result.Output)
+		}
+	}
+}
+
+func TestNewCommand_ReadsEnvironmentVariables(t *testing.T) {
+	base := t.TempDir()
+	projectDir := filepath.Join(base, "demo")
+	env := append(baseEnv(t),
+		"GOKICKSTART_MODULE=github.com/acme/demo",
+		"GOKICKSTART_NO_GIT=true",
+		"GOKICKSTART_WEB=false",
+		"GOKICKSTART_DB_PASSWORD=from-env",
+	)
+
+	// The flag wins over GOKICKSTART_WEB.
+	result := runCLI(t, env, "new", "demo", base, "--web")
+	if result.Err != nil {
+		t.Fatalf("expected scaffold to succeed: %v\n%s", result.Err, result.Output)
+	}
+	assertExists(t, filepath.Join(projectDir, "apps/web"))
+	assertNotExists(t, filepath.Join(projectDir, ".git"))
+	assertFileContains(t, filepath.Join(projectDir, "apps/api/.env"), "from-env")
+}
+
 type runResult struct {
 	Output string
 	Err    error
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/generate"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/upgrade"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addProjectPath string
+	featureFlags   struct {
+		observability string
+		s3            scaffold.S3Config
+	}
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add code to an existing generated project",
+}
+
+func init() {
+	resourceCmd := &cobra.Command{
+		Use:   "resource <Name> <field:type[:nullable][:unique][:indexed]>...",
+		Short: "Generate a CRUD resource slice (model, migration, service, handler, routes, contracts)",
+		Long: "Generate a CRUD resource slice inside a go-kickstart project.\n\n" +
+			"Field types: string, text, int, int64, float, bool, time, uuid.\n" +
+			"Modifiers: nullable, unique, indexed.\n\n" +
+			"Example:\n  gokickstart add resource Product title:string sku:string:unique price:float description:text:nullable",
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAddResource(addProjectPath, args[0], args[1:])
+		},
+	}
+	featureCmd := &cobra.Command{
+		Use:   "feature <" + strings.Join(upgrade.FeatureNames, "|") + ">",
+		Short: "Enable a feature the project was generated without",
+		Long: "Render the project's template with the recorded configuration and again with the\n" +
+			"feature enabled, and apply the difference to the project like `gokickstart upgrade`:\n" +
+			"files you never touched are updated in place, files you edited are merged and\n" +
+			"conflicts are marked with <<<<<<< / >>>>>>> (or written next to the file as\n" +
+			"<file>.rej when a new file would replace one of yours).\n\n" +
+			"Opt-in features declared by the template manifest are accepted as well. The project\n" +
+			"must still match the template revision it was generated from.",
+		Args: cobra.ExactArgs(1),
+		// Unknown features and conflicts are reported by the command itself.
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := upgrade.FeatureOptions{Observability: scaffold.ObservabilityProvider(featureFlags.observability)}
+			if featureFlags.s3 != (scaffold.S3Config{}) {
+				s3 := featureFlags.s3
+				opts.S3 = &s3
+			}
+			return runAddFeature(addProjectPath, args[0], opts)
+		},
+	}
+	featureCmd.Flags().StringVar(&featureFlags.observability, "observability", string(scaffold.ObservabilityGrafanaOSS), "observability stack to add (grafana-oss|otlp)")
+	featureCmd.Flags().StringVar(&featureFlags.s3.Endpoint, "s3-endpoint", "", "s3 endpoint")
+	featureCmd.Flags().StringVar(&featureFlags.s3.Region, "s3-region", "", "s3 region")
+	featureCmd.Flags().StringVar(&featureFlags.s3.Bucket, "s3-bucket", "", "s3 bucket")
+	featureCmd.Flags().StringVar(&featureFlags.s3.AccessKey, "s3-access-key", "", "s3 access key")
+	featureCmd.Flags().StringVar(&featureFlags.s3.SecretKey, "s3-secret-key", "", "s3 secret key")
+
+	addCmd.PersistentFlags().StringVar(&addProjectPath, "path", ".", "path inside the generated project")
+	addCmd.AddCommand(resourceCmd, featureCmd)
+	rootCmd.AddCommand(addCmd)
+}
+
+func runAddResource(path string, name string, specs []string) error {
+	fields, err := generate.ParseFields(specs)
+	if err != nil {
+		return err
+	}
+	project, err := generate.FindProject(path)
+	if err != nil {
+		return err
+	}
+	result, err := generate.AddResource(project, name, fields)
+	if err != nil {
+		return err
+	}
+	ui.PrintResourceSummary(generate.NewNames(name).Pascal, project.PackageManager, result.Created, result.Updated)
+	return nil
+}
+
+func runAddFeature(dir, feature string, opts upgrade.FeatureOptions) error {
+	root, err := scaffold.FindManifestRoot(dir)
+	if err != nil {
+		return err
+	}
+	result, err := upgrade.AddFeature(root, feature, opts)
+	if err != nil {
+		return err
+	}
+	if len(result.Changes) == 0 {
+		ui.PrintUpgradeSummary(fmt.Sprintf("✅ Enabled %s; no files changed", feature), nil, nil)
+		return nil
+	}
+
+	var goMod, packageJSON bool
+	for _, change := range result.Changes {
+		switch path.Base(change.Path) {
+		case "go.mod":
+			goMod = true
+		case "package.json":
+			packageJSON = true
+		}
+	}
+	var steps []string
+	if goMod {
+		steps = append(steps, "run `go mod tidy` in each module whose go.mod changed")
+	}
+	if packageJSON {
+		cfg := scaffold.DefaultConfig()
+		if manifest, err := scaffold.ReadManifest(root); err == nil {
+			manifest.Config.Apply(&cfg)
+		}
+		steps = append(steps, fmt.Sprintf("install the new dependencies with `%s install`", cfg.PackageManager))
+	}
+	ui.PrintUpgradeSummary("✅ Enabled "+feature, changeGroups(result), changeHints(result, steps...))
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/doctor"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var doctorJSON bool
+
+func init() {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor [path]",
+		Short: "Check that this machine can run a generated project",
+		Long: "Check the Go, package manager and Node versions the project asks for, Docker and\n" +
+			"compose, and that the compose ports are free. Inside a generated project the .env\n" +
+			"files are also compared with their .env.example and validated against the API config.\n" +
+			"Outside a project the checks use the files the default template would generate.",
+		Args: cobra.MaximumNArgs(1),
+		// Failing checks are reported by the command itself, not usage errors.
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runDoctor(path, doctorJSON)
+		},
+	}
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "print the report as JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(path string, asJSON bool) error {
+	opts := doctor.Options{}
+	title := "🩺 Doctor (no project found, checking the default template)"
+	if root, err := scaffold.FindManifestRoot(path); err == nil {
+		opts.Root = root
+		opts.Files = os.DirFS(root)
+		title = "🩺 Doctor: " + root
+	} else {
+		dir, err := defaultProjectFiles()
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		opts.Files = os.DirFS(dir)
+	}
+
+	report := doctor.Run(opts)
+	failed := report.Count(doctor.StatusFail)
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		checks := make([]ui.DoctorCheck, len(report.Checks))
+		for i, check := range report.Checks {
+			checks[i] = ui.DoctorCheck{Group: check.Group, Name: check.Name, Status: string(check.Status), Detail: check.Detail, Fix: check.Fix}
+		}
+		summary := fmt.Sprintf("%d passed, %d warnings, %d failed", report.Count(doctor.StatusPass), report.Count(doctor.StatusWarn), failed)
+		ui.PrintDoctor(title, checks, summary)
+	}
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d failing check(s)", failed)
+	}
+	return nil
+}
+
+// defaultProjectFiles renders the embedded template with the default options
+// into a temporary directory and returns it. The caller removes it.
+func defaultProjectFiles() (string, error) {
+	source, err := scaffold.EmbeddedTemplate()
+	if err != nil {
+		return "", err
+	}
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "doctor"
+	cfg.ModulePath = "example.com/doctor"
+	plan, err := scaffold.PlanProject(cfg, source, scaffold.EnvOverridesFromConfig(cfg))
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "gokickstart-doctor-")
+	if err != nil {
+		return "", err
+	}
+	for _, file := range plan.Files {
+		path := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// envPrefix prefixes the environment variables that stand in for flags.
+const envPrefix = "GOKICKSTART_"
+
+// flagEnvName is the environment variable for the flag called name, e.g.
+// GOKICKSTART_DB_PASSWORD for --db-password.
+func flagEnvName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyEnvFlags sets every flag that was not given on the command line from
+// its GOKICKSTART_* environment variable. The flag then counts as given, so
+// the command line wins over the environment, and the environment over config
+// files and presets.
+func applyEnvFlags(fs *pflag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+		value, ok := os.LookupEnv(flagEnvName(flag.Name))
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("%s: %w", flagEnvName(flag.Name), setErr)
+		}
+	})
+	return err
+}
+
+// stdioIsTerminal reports whether stdin and stdout are both terminals, which
+// the wizard needs.
+func stdioIsTerminal() bool {
+	for _, file := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := file.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// noTerminalError explains why the wizard cannot run and lists the inputs a
+// non-interactive run still needs.
+func noTerminalError(args []string, f newFlags) error {
+	msg := "the interactive wizard needs a terminal, but stdin or stdout is not one"
+	var missing []string
+	if len(args) == 0 && f.name == "" {
+		missing = append(missing, "project name: first argument, --name or "+flagEnvName("name"))
+	}
+	if f.modulePath == "" {
+		missing = append(missing, "module path: --module or "+flagEnvName("module"))
+	}
+	if len(missing) == 0 {
+		return fmt.Errorf("%s; drop --interactive to generate without it", msg)
+	}
+	return fmt.Errorf("%s. To generate without it, provide (or pass --config):\n  - %s", msg, strings.Join(missing, "\n  - "))
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestApplyEnvFlagsLetsFlagsWin(t *testing.T) {
+	fs := pflag.NewFlagSet("new", pflag.ContinueOnError)
+	var module, password string
+	var web bool
+	var features []string
+	fs.StringVar(&module, "module", "", "")
+	fs.StringVar(&password, "db-password", "", "")
+	fs.BoolVar(&web, "web", true, "")
+	fs.StringSliceVar(&features, "feature", nil, "")
+	if err := fs.Parse([]string{"--module", "github.com/acme/flag"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	t.Setenv("GOKICKSTART_MODULE", "github.com/acme/env")
+	t.Setenv("GOKICKSTART_DB_PASSWORD", "from-env")
+	t.Setenv("GOKICKSTART_WEB", "false")
+	t.Setenv("GOKICKSTART_FEATURE", "queue,worker")
+
+	if err := applyEnvFlags(fs); err != nil {
+		t.Fatalf("apply env: %v", err)
+	}
+	if module != "github.com/acme/flag" {
+		t.Fatalf("expected the flag to win over the environment, got %s", module)
+	}
+	if password != "from-env" || web || strings.Join(features, ",") != "queue,worker" {
+		t.Fatalf("expected values from the environment, got %q %t %v", password, web, features)
+	}
+	if !fs.Changed("db-password") {
+		t.Fatalf("expected an environment value to count as given, so it wins over config files")
+	}
+
+	t.Setenv("GOKICKSTART_WEB", "maybe")
+	fs.Lookup("web").Changed = false
+	if err := applyEnvFlags(fs); err == nil || !strings.Contains(err.Error(), "GOKICKSTART_WEB") {
+		t.Fatalf("expected an invalid value to name the variable, got %v", err)
+	}
+}
+
+func TestNoTerminalErrorListsMissingInputs(t *testing.T) {
+	err := noTerminalError(nil, newFlags{})
+	for _, want := range []string{"needs a terminal", "GOKICKSTART_NAME", "--module or GOKICKSTART_MODULE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+	err = noTerminalError([]string{"demo"}, newFlags{modulePath: "github.com/acme/demo"})
+	if strings.Contains(err.Error(), "GOKICKSTART_NAME") || !strings.Contains(err.Error(), "--interactive") {
+		t.Fatalf("expected only the --interactive hint, got %v", err)
+	}
+}
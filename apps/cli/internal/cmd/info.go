@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var infoJSON bool
+
+func init() {
+	infoCmd := &cobra.Command{
+		Use:   "info [path]",
+		Short: "Show how a generated project was created",
+		Long: "Read .gokickstart/manifest.json from a generated project and report the CLI version,\n" +
+			"template revision and options that produced it, plus files changed since generation.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runInfo(path, infoJSON)
+		},
+	}
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "print the manifest as JSON")
+	rootCmd.AddCommand(infoCmd)
+}
+
+func runInfo(path string, asJSON bool) error {
+	root, err := scaffold.FindManifestRoot(path)
+	if err != nil {
+		return err
+	}
+	manifest, err := scaffold.ReadManifest(root)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(manifest)
+	}
+
+	statuses, err := manifest.VerifyFiles(root)
+	if err != nil {
+		return err
+	}
+	var modified, missing []string
+	for file, status := range statuses {
+		switch status {
+		case scaffold.FileModified:
+			modified = append(modified, file)
+		case scaffold.FileMissing:
+			missing = append(missing, file)
+		}
+	}
+	sort.Strings(modified)
+	sort.Strings(missing)
+
+	cfg := manifest.Config
+	templateStatus := "differs from this CLI's template"
+	if cfg.Template != "" {
+		templateStatus = "from " + cfg.Template
+	} else if source, err := scaffold.EmbeddedTemplate(); err == nil {
+		if current, err := scaffold.HashTemplate(source); err == nil && current == manifest.TemplateHash {
+			templateStatus = "matches this CLI's template"
+		}
+	}
+
+	rows := []ui.InfoRow{
+		{Label: "Path", Value: root},
+		{Label: "Module", Value: cfg.Module},
+		{Label: "Generated by", Value: fmt.Sprintf("gokickstart %s on %s", manifest.GeneratorVersion, manifest.GeneratedAt.Format("2006-01-02 15:04 MST"))},
+		{Label: "Template", Value: fmt.Sprintf("%s (%s)", shortHash(manifest.TemplateHash), templateStatus)},
+		{Label: "Components", Value: components(cfg)},
+		{Label: "Options", Value: options(cfg)},
+		{Label: "Files", Value: fmt.Sprintf("%d tracked, %d modified, %d missing", len(manifest.Files), len(modified), len(missing))},
+	}
+	ui.PrintInfo(fmt.Sprintf("📋 %s", cfg.Name), rows, modified, missing)
+	return nil
+}
+
+func shortHash(hash string) string {
+	prefix, sum, ok := strings.Cut(hash, ":")
+	if !ok || len(sum) <= 12 {
+		return hash
+	}
+	return prefix + ":" + sum[:12]
+}
+
+func components(cfg scaffold.ConfigFile) string {
+	parts := []string{"api"}
+	if cfg.Web != nil && *cfg.Web {
+		parts = append(parts, "web")
+	}
+	if cfg.Docker != nil && *cfg.Docker {
+		parts = append(parts, "docker")
+	}
+	if cfg.Git != nil && *cfg.Git {
+		parts = append(parts, "git")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func options(cfg scaffold.ConfigFile) string {
+	parts := []string{"packageManager=" + cfg.PackageManager, "observability=" + cfg.Observability}
+	if cfg.Database != nil {
+		parts = append(parts, "database="+cfg.Database.Type)
+	}
+	if cfg.Storage != nil {
+		parts = append(parts, "storage="+cfg.Storage.Type)
+	}
+	if cfg.Project != nil && cfg.Project.License != "" {
+		parts = append(parts, "license="+cfg.Project.License)
+	}
+	if len(cfg.Features) > 0 {
+		parts = append(parts, "features="+strings.Join(cfg.Features, ","))
+	}
+	return strings.Join(parts, " ")
+}
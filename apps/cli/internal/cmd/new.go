@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/prompts"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/templatesource"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/validate"
 	"github.com/spf13/cobra"
@@ -27,6 +29,12 @@ type newFlags struct {
 	noDocker      bool
 	git           bool
 	noGit         bool
+	gitBranch     string
+	gitRemote     string
+	gitAuthor     string
+	gitEmail      string
+	gitMessage    string
+	gitNoCommit   bool
 	db            string
 	dbHost        string
 	dbPort        string
@@ -34,14 +42,56 @@ type newFlags struct {
 	dbPassword    string
 	dbName        string
 	dbSSLMode     string
+	apiPort       string
+	webPort       string
 	pkg           string
 	storage       string
 	observability string
+	license       string
+	description   string
+	author        string
+	homepage      string
 	s3Endpoint    string
 	s3Region      string
 	s3Bucket      string
 	s3Access      string
 	s3Secret      string
+	configPath    string
+	saveConfig    string
+	preset        string
+	savePreset    string
+	dryRun        bool
+	diffDir       string
+	onConflict    string
+	template      string
+	output        string
+	features      []string
+	install       bool
+	verify        bool
+	skipSteps     []string
+	// set reports whether a flag was given on the command line. Flags that
+	// were not given leave config file values alone; nil treats every flag as
+	// given.
+	set func(name string) bool
+}
+
+func (f newFlags) changed(name string) bool {
+	return f.set == nil || f.set(name)
+}
+
+// runOptions controls what happens once the configuration is final.
+type runOptions struct {
+	saveConfig string
+	savePreset string
+	dryRun     bool
+	diffDir    string
+	onConflict scaffold.ConflictPolicy
+	install    bool
+	verify     bool
+	skipSteps  []string
+	output     string
+	// observe receives the generation events instead of the text output.
+	observe func(scaffold.Event)
 }
 
 var (
@@ -52,15 +102,22 @@ var (
 	databaseFlowFn              = prompts.DatabaseFlow
 	storageFlowFn               = prompts.StorageFlow
 	observabilityFlowFn         = prompts.ObservabilityFlow
+	projectFlowFn               = prompts.ProjectFlow
 	reviewConfigFn              = prompts.ReviewConfig
 	validateProjectNameFn       = validate.ProjectName
 	validateModulePathFn        = validate.ModulePath
 	resolveProjectDestinationFn = validate.ResolveProjectDestination
 	isNonEmptyDirFn             = validate.IsNonEmptyDir
 	showWelcomeFn               = showWelcomeScreen
-	runWithSpinnerFn            = ui.RunWithSpinner
+	runWithProgressFn           = ui.RunWithProgress
 	scaffoldProjectFn           = scaffold.ScaffoldProject
 	printSummaryFn              = ui.PrintSummary
+	hasGitIdentityFn            = scaffold.HasGitIdentity
+	gitIdentityFlowFn           = prompts.GitIdentityFlow
+	loadPresetsFn               = loadPresets
+	isTerminalFn                = stdioIsTerminal
+	chooseConflictPolicyFn      = prompts.ConflictPolicy
+	resolveConflictFn           = prompts.ResolveConflict
 )
 
 func init() {
@@ -68,15 +125,105 @@ func init() {
 		Use:   "new [name] [path]",
 		Short: "Create a new project",
 		Args:  cobra.RangeArgs(0, 2),
+		Long: "Create a new project with the interactive wizard, or non-interactively from arguments,\n" +
+			"flags, a config file and a preset.\n\n" +
+			"Every flag can also be set with a GOKICKSTART_* environment variable named after it\n" +
+			"(GOKICKSTART_DB_PASSWORD for --db-password). Precedence, highest first: flags, environment,\n" +
+			"config file, preset, defaults.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if interactive || len(args) == 0 {
-				return runInteractive()
+			if err := applyEnvFlags(cmd.Flags()); err != nil {
+				return err
+			}
+			flags.set = cmd.Flags().Changed
+			if flags.diffDir != "" && !flags.dryRun {
+				return errors.New("--diff requires --dry-run")
+			}
+			if err := scaffold.CheckStepNames(flags.skipSteps); err != nil {
+				return err
+			}
+			if flags.savePreset != "" {
+				if err := scaffold.CheckPresetName(flags.savePreset); err != nil {
+					return err
+				}
+			}
+			opts := runOptions{
+				saveConfig: flags.saveConfig,
+				savePreset: flags.savePreset,
+				dryRun:     flags.dryRun,
+				diffDir:    flags.diffDir,
+				install:    flags.install,
+				verify:     flags.verify,
+				skipSteps:  flags.skipSteps,
+				output:     flags.output,
+			}
+			switch flags.output {
+			case outputText:
+			case outputJSON:
+				if flags.dryRun || flags.onConflict == string(scaffold.ConflictPrompt) {
+					return errors.New("--output json cannot be combined with --dry-run or --on-conflict=prompt")
+				}
+			default:
+				return fmt.Errorf("unsupported output %q (use text|json)", flags.output)
+			}
+			if flags.onConflict != "" {
+				policy, err := scaffold.ParseConflictPolicy(flags.onConflict)
+				if err != nil {
+					return err
+				}
+				opts.onConflict = policy
+			}
+			if opts.onConflict == scaffold.ConflictPrompt && !isTerminalFn() {
+				return errors.New("--on-conflict=prompt needs a terminal; choose skip, overwrite or backup")
+			}
+			if opts.output == outputJSON {
+				// Everything on stdout is NDJSON, so the wizard is never shown
+				// and errors are reported in the result instead of with usage.
+				cmd.SilenceUsage = true
+				stream := newJSONStream(os.Stdout)
+				opts.observe = stream.observe
+				cfg, err := configFromFlags(args, flags)
+				if err != nil {
+					stream.finish(cfg, scaffold.CodeInvalidConfig, err)
+					return err
+				}
+				err = runNonInteractive(cfg, opts)
+				stream.finish(cfg, "", err)
+				return err
+			}
+			if interactive || (len(args) == 0 && flags.configPath == "" && flags.name == "") {
+				if !isTerminalFn() {
+					cmd.SilenceUsage = true
+					return noTerminalError(args, flags)
+				}
+				initial := scaffold.DefaultConfig()
+				if err := applyPreset(&initial, flags.preset); err != nil {
+					return err
+				}
+				if flags.configPath != "" {
+					file, err := scaffold.LoadConfigFile(flags.configPath)
+					if err != nil {
+						return err
+					}
+					file.Apply(&initial)
+				}
+				if flags.template != "" {
+					initial.Template = flags.template
+				}
+				if len(flags.features) > 0 {
+					initial.Features = flags.features
+				}
+				template, err := templatesource.Absolute(initial.Template)
+				if err != nil {
+					return err
+				}
+				initial.Template = template
+				return runInteractive(initial, opts)
 			}
 			cfg, err := configFromFlags(args, flags)
 			if err != nil {
 				return err
 			}
-			return runNonInteractive(cfg)
+			return runNonInteractive(cfg, opts)
 		},
 	}
 	newCmd.Flags().BoolVar(&interactive, "interactive", false, "force interactive wizard")
@@ -88,76 +235,82 @@ func init() {
 	newCmd.Flags().BoolVar(&flags.noDocker, "no-docker", false, "exclude docker compose")
 	newCmd.Flags().BoolVar(&flags.git, "git", true, "initialize git repository")
 	newCmd.Flags().BoolVar(&flags.noGit, "no-git", false, "do not initialize git repository")
-	newCmd.Flags().StringVar(&flags.db, "db", "postgres", "database type (postgres)")
+	newCmd.Flags().StringVar(&flags.gitBranch, "git-branch", "", "initial branch of the git repository")
+	newCmd.Flags().StringVar(&flags.gitRemote, "git-remote", "", "URL to add as the origin remote")
+	newCmd.Flags().StringVar(&flags.gitAuthor, "git-author-name", "", "author name of the initial commit")
+	newCmd.Flags().StringVar(&flags.gitEmail, "git-author-email", "", "author email of the initial commit")
+	newCmd.Flags().StringVar(&flags.gitMessage, "git-message", "", "message of the initial commit")
+	newCmd.Flags().BoolVar(&flags.gitNoCommit, "git-no-commit", false, "stage the generated files without committing them")
+	newCmd.Flags().StringVar(&flags.db, "db", "postgres", "database type (postgres|mysql)")
 	newCmd.Flags().StringVar(&flags.dbHost, "db-host", "", "database host")
 	newCmd.Flags().StringVar(&flags.dbPort, "db-port", "", "database port")
 	newCmd.Flags().StringVar(&flags.dbUser, "db-user", "", "database user")
 	newCmd.Flags().StringVar(&flags.dbPassword, "db-password", "", "database password")
 	newCmd.Flags().StringVar(&flags.dbName, "db-name", "", "database name")
 	newCmd.Flags().StringVar(&flags.dbSSLMode, "db-ssl-mode", "", "database ssl mode")
-	newCmd.Flags().StringVar(&flags.pkg, "pkg", "bun", "package manager (bun)")
+	newCmd.Flags().StringVar(&flags.apiPort, "api-port", "", "local port of the API (default 8080)")
+	newCmd.Flags().StringVar(&flags.webPort, "web-port", "", "local port of the web app (default 3000)")
+	newCmd.Flags().StringVar(&flags.pkg, "pkg", "bun", "package manager (bun|npm|pnpm|yarn)")
 	newCmd.Flags().StringVar(&flags.storage, "storage", "local", "storage type (local|s3)")
-	newCmd.Flags().StringVar(&flags.observability, "observability", string(scaffold.ObservabilityNone), "observability stack (none|grafana-oss)")
+	newCmd.Flags().StringVar(&flags.observability, "observability", string(scaffold.ObservabilityNone), "observability stack (none|grafana-oss|otlp)")
+	newCmd.Flags().StringVar(&flags.license, "license", string(scaffold.LicenseMIT), "license of the project (MIT|Apache-2.0|BSD-3|proprietary)")
+	newCmd.Flags().StringVar(&flags.description, "description", "", "one-line project description for package.json, the README and image labels")
+	newCmd.Flags().StringVar(&flags.author, "author", "", "project author and copyright holder (default \"The <name> authors\")")
+	newCmd.Flags().StringVar(&flags.homepage, "homepage", "", "project homepage URL (default https://<module path>)")
 	newCmd.Flags().StringVar(&flags.s3Endpoint, "s3-endpoint", "", "s3 endpoint")
 	newCmd.Flags().StringVar(&flags.s3Region, "s3-region", "", "s3 region")
 	newCmd.Flags().StringVar(&flags.s3Bucket, "s3-bucket", "", "s3 bucket")
 	newCmd.Flags().StringVar(&flags.s3Access, "s3-access-key", "", "s3 access key")
 	newCmd.Flags().StringVar(&flags.s3Secret, "s3-secret-key", "", "s3 secret key")
+	newCmd.Flags().StringVar(&flags.configPath, "config", "", "load project settings from a YAML or JSON config file")
+	newCmd.Flags().StringVar(&flags.preset, "preset", "", "start from a built-in or user preset (see `gokickstart presets`)")
+	newCmd.Flags().StringVar(&flags.savePreset, "save-preset", "", "save the final project settings as a user preset with this name")
+	newCmd.Flags().StringVar(&flags.saveConfig, "save-config", "", "write the final project settings to a YAML or JSON config file")
+	newCmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "print the files that would be generated without writing anything")
+	newCmd.Flags().StringVar(&flags.diffDir, "diff", "", "with --dry-run, print a unified diff against an existing directory")
+	newCmd.Flags().StringVar(&flags.onConflict, "on-conflict", "", "how to handle existing files in a non-empty destination (skip|overwrite|backup|prompt)")
+	newCmd.Flags().StringSliceVar(&flags.features, "feature", nil, "enable an optional template feature (repeatable)")
+	newCmd.Flags().BoolVar(&flags.install, "install", false, "after generating, run go mod tidy, install workspace dependencies and generate the OpenAPI client and emails")
+	newCmd.Flags().BoolVar(&flags.verify, "verify", false, "after generating, run go mod tidy, go build and go vet in apps/api")
+	newCmd.Flags().StringSliceVar(&flags.skipSteps, "skip-step", nil, "skip a post-generation step ("+strings.Join(scaffold.StepNames, "|")+", repeatable)")
+	newCmd.Flags().StringVar(&flags.output, "output", outputText, "output format (text|json); json streams NDJSON progress events and a final result object")
+	newCmd.Flags().StringVar(&flags.template, "template", "", "template directory, .tar.gz/.zip pack or git repository at a ref (path#ref)")
 	rootCmd.AddCommand(newCmd)
 }
 
-func runInteractive() error {
+func runInteractive(initial scaffold.ScaffoldConfiguration, opts runOptions) error {
 	if err := showWelcomeFn(); err != nil {
 		return err
 	}
 
-	cfg := scaffold.DefaultConfig()
-	flow := prompts.FlowBasic
-
+	cfg := initial
+	presets, err := loadPresetsFn()
+	if err != nil {
+		return err
+	}
+	if err := runFlow(&cfg, presets); err != nil {
+		return err
+	}
 	for {
-		choice, err := chooseFlowFn()
-		if err != nil {
-			return err
-		}
-		flow = choice
-		cfg.UseDefaults = flow == prompts.FlowBasic
-
-		result, err := basicFlowFn(cfg)
+		action, err := reviewConfigFn(cfg)
 		if err != nil {
 			return err
 		}
-		cfg = result
-
-		if flow == prompts.FlowAdvanced {
-			cfg.UseDefaults = false
-			if err := destinationFlowFn(&cfg); err != nil {
-				return err
-			}
-			if err := componentsFlowFn(&cfg); err != nil {
-				return err
-			}
-			if err := databaseFlowFn(&cfg); err != nil {
-				return err
-			}
-			if err := storageFlowFn(&cfg); err != nil {
+		switch action {
+		case prompts.ReviewGenerate:
+			// proceed
+		case prompts.ReviewCancel:
+			return errors.New("cancelled")
+		case prompts.ReviewEdit:
+			if err := runFlow(&cfg, presets); err != nil {
 				return err
 			}
-			if err := observabilityFlowFn(&cfg); err != nil {
+			continue
+		default:
+			if err := editSection(&cfg, action); err != nil {
 				return err
 			}
-		}
-
-		action, err := reviewConfigFn(cfg)
-		if err != nil {
-			return err
-		}
-		switch action {
-		case prompts.ReviewEdit:
 			continue
-		case prompts.ReviewCancel:
-			return errors.New("cancelled")
-		case prompts.ReviewGenerate:
-			// proceed
 		}
 		break
 	}
@@ -174,44 +327,158 @@ func runInteractive() error {
 	}
 	cfg.Destination = dest
 
+	if opts.dryRun {
+		return printPlan(cfg, opts.diffDir)
+	}
+
+	if cfg.Secrets, err = scaffold.GenerateSecrets(cfg); err != nil {
+		return err
+	}
 	nonEmpty, err := isNonEmptyDirFn(dest)
 	if err != nil {
 		return err
 	}
-	allowOverwrite := false
+	var resolve scaffold.ConflictResolver
 	if nonEmpty {
-		confirm := false
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewConfirm().
-					Title(fmt.Sprintf("%s\n%s", ui.OverwriteTitle, dest)).
-					Description("Existing files detected. Proceed only if this is intentional.").
-					Affirmative(ui.OverwriteAff).
-					Negative(ui.OverwriteNeg).
-					Value(&confirm),
-			),
-		)
-		form.WithTheme(ui.HuhTheme())
-		form.WithWidth(90)
-		form.WithHeight(12)
-		form.WithOutput(os.Stdout)
-		if err := form.Run(); err != nil {
+		policy := opts.onConflict
+		if policy == "" {
+			if policy, err = chooseConflictPolicyFn(dest); err != nil {
+				return err
+			}
+			if policy == "" {
+				return errors.New("cancelled")
+			}
+		}
+		if resolve, err = conflictResolver(cfg, policy); err != nil {
 			return err
 		}
-		if !confirm {
-			return errors.New("cancelled")
+	}
+
+	if cfg.InitGit && !cfg.Git.NoCommit && !hasGitIdentityFn(cfg.Git) {
+		if err := gitIdentityFlowFn(&cfg); err != nil {
+			return err
 		}
-		allowOverwrite = true
 	}
 
-	err = runWithSpinnerFn("Generating project...", func() error {
-		return scaffoldProjectFn(cfg, allowOverwrite)
+	steps, err := scaffold.PostGenerateSteps(cfg, opts.install, opts.verify, opts.skipSteps)
+	if err != nil {
+		return err
+	}
+	var warnings warningLog
+	err = runWithProgressFn("Generating project...", func(progress ui.Progress) error {
+		return scaffoldProjectFn(cfg, scaffold.Options{
+			Resolve: resolve,
+			Steps:   steps,
+			Observe: warnings.observe(progressObserver(progress)),
+		})
 	})
+	return finishGeneration(cfg, opts, steps, warnings, err, printSummaryFn)
+}
+
+// runFlow asks how to configure the project and runs the chosen flow.
+func runFlow(cfg *scaffold.ScaffoldConfiguration, presets []scaffold.Preset) error {
+	flow, presetName, err := chooseFlowFn(presets)
 	if err != nil {
 		return err
 	}
+	cfg.UseDefaults = flow != prompts.FlowAdvanced
+	if flow == prompts.FlowPreset {
+		preset, err := scaffold.FindPreset(presets, presetName)
+		if err != nil {
+			return err
+		}
+		preset.Apply(cfg)
+	}
+	if err := editSection(cfg, prompts.ReviewEditBasics); err != nil {
+		return err
+	}
+	if flow != prompts.FlowAdvanced {
+		return nil
+	}
+	for _, section := range []prompts.ReviewAction{
+		prompts.ReviewEditProject,
+		prompts.ReviewEditDestination,
+		prompts.ReviewEditComponents,
+		prompts.ReviewEditDatabase,
+		prompts.ReviewEditStorage,
+		prompts.ReviewEditObservability,
+	} {
+		if err := editSection(cfg, section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	printSummaryFn(cfg.Destination)
+// editSection runs the flow behind a review section; the rest of cfg is kept.
+func editSection(cfg *scaffold.ScaffoldConfiguration, section prompts.ReviewAction) error {
+	if section == prompts.ReviewEditBasics {
+		result, err := basicFlowFn(*cfg)
+		if err != nil {
+			return err
+		}
+		*cfg = result
+		return nil
+	}
+	flows := map[prompts.ReviewAction]func(*scaffold.ScaffoldConfiguration) error{
+		prompts.ReviewEditProject:       projectFlowFn,
+		prompts.ReviewEditDestination:   destinationFlowFn,
+		prompts.ReviewEditComponents:    componentsFlowFn,
+		prompts.ReviewEditDatabase:      databaseFlowFn,
+		prompts.ReviewEditStorage:       storageFlowFn,
+		prompts.ReviewEditObservability: observabilityFlowFn,
+	}
+	flow, ok := flows[section]
+	if !ok {
+		return fmt.Errorf("unknown review action %q", section)
+	}
+	cfg.UseDefaults = false
+	return flow(cfg)
+}
+
+// finishGeneration saves the config and prints the summary once the project
+// is in place. A failed post-generation step still leaves a usable project,
+// so the config is saved before the step error is reported.
+func finishGeneration(cfg scaffold.ScaffoldConfiguration, opts runOptions, steps []scaffold.Step, warnings warningLog, err error, printSummary func(ui.Summary)) error {
+	var stepErr *scaffold.StepError
+	if err != nil && !errors.As(err, &stepErr) {
+		return err
+	}
+	if opts.saveConfig != "" {
+		if err := scaffold.SaveConfigFile(opts.saveConfig, cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+	}
+	if opts.savePreset != "" {
+		dir, err := scaffold.DefaultPresetDir()
+		if err != nil {
+			return fmt.Errorf("save preset: %w", err)
+		}
+		if _, err := scaffold.SavePreset(dir, opts.savePreset, cfg); err != nil {
+			return fmt.Errorf("save preset: %w", err)
+		}
+	}
+	if stepErr != nil {
+		return fmt.Errorf("%w\n\nThe project was generated in %s. Fix the problem and run `%s` there, or skip the step with --skip-step %s", stepErr, cfg.Destination, stepErr.Step, stepErr.Step.Name)
+	}
+	if opts.output == outputJSON {
+		return nil
+	}
+	summary := ui.Summary{
+		Path:           cfg.Destination,
+		PackageManager: string(cfg.PackageManager),
+		Staged:         cfg.InitGit && (cfg.Git.NoCommit || warnings.has(scaffold.PhaseGit)),
+	}
+	for _, step := range steps {
+		summary.Installed = summary.Installed || step.Name == scaffold.StepInstall
+	}
+	for _, secret := range cfg.Secrets.Generated(cfg) {
+		summary.Secrets = append(summary.Secrets, fmt.Sprintf("%s → %s", secret.Key, strings.Join(secret.Files, ", ")))
+	}
+	for _, warning := range warnings {
+		summary.Warnings = append(summary.Warnings, warning.Message)
+	}
+	printSummary(summary)
 	return nil
 }
 
@@ -236,6 +503,21 @@ func showWelcomeScreen() error {
 func configFromFlags(args []string, flags newFlags) (scaffold.ScaffoldConfiguration, error) {
 	cfg := scaffold.DefaultConfig()
 	cfg.UseDefaults = false
+	// Name and module have no sensible non-interactive default; they must come
+	// from the config file, args or flags.
+	cfg.ProjectName = ""
+	cfg.ModulePath = ""
+
+	if err := applyPreset(&cfg, flags.preset); err != nil {
+		return cfg, err
+	}
+	if flags.configPath != "" {
+		file, err := scaffold.LoadConfigFile(flags.configPath)
+		if err != nil {
+			return cfg, err
+		}
+		file.Apply(&cfg)
+	}
 
 	if len(args) > 0 {
 		cfg.ProjectName = args[0]
@@ -250,26 +532,51 @@ func configFromFlags(args []string, flags newFlags) (scaffold.ScaffoldConfigurat
 		cfg.Destination = args[1]
 	}
 
-	if flags.modulePath == "" {
+	if flags.modulePath != "" {
+		cfg.ModulePath = flags.modulePath
+	}
+	if cfg.ModulePath == "" {
 		return cfg, errors.New("module path is required (--module)")
 	}
-	cfg.ModulePath = flags.modulePath
 
-	cfg.IncludeWeb = flags.web
-	if flags.noWeb {
+	if flags.changed("web") {
+		cfg.IncludeWeb = flags.web
+	}
+	if flags.changed("no-web") && flags.noWeb {
 		cfg.IncludeWeb = false
 	}
-	cfg.IncludeDocker = flags.docker
-	if flags.noDocker {
+	if flags.changed("docker") {
+		cfg.IncludeDocker = flags.docker
+	}
+	if flags.changed("no-docker") && flags.noDocker {
 		cfg.IncludeDocker = false
 	}
-	cfg.InitGit = flags.git
-	if flags.noGit {
+	if flags.changed("git") {
+		cfg.InitGit = flags.git
+	}
+	if flags.changed("no-git") && flags.noGit {
 		cfg.InitGit = false
 	}
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&cfg.Git.Branch, flags.gitBranch},
+		{&cfg.Git.Remote, flags.gitRemote},
+		{&cfg.Git.AuthorName, flags.gitAuthor},
+		{&cfg.Git.AuthorEmail, flags.gitEmail},
+		{&cfg.Git.CommitMessage, flags.gitMessage},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if flags.changed("git-no-commit") {
+		cfg.Git.NoCommit = flags.gitNoCommit
+	}
 
-	if flags.db != "" {
-		cfg.DatabaseType = scaffold.DatabaseType(flags.db)
+	if flags.changed("db") && flags.db != "" {
+		scaffold.SetDatabaseType(&cfg, scaffold.DatabaseType(flags.db))
 	}
 	if flags.dbHost != "" {
 		cfg.DBConnection.Host = flags.dbHost
@@ -289,40 +596,76 @@ func configFromFlags(args []string, flags newFlags) (scaffold.ScaffoldConfigurat
 	if flags.dbSSLMode != "" {
 		cfg.DBConnection.SSLMode = flags.dbSSLMode
 	}
+	if flags.apiPort != "" {
+		cfg.APIPort = flags.apiPort
+	}
+	if flags.webPort != "" {
+		cfg.WebPort = flags.webPort
+	}
 
-	cfg.PackageManager = scaffold.PackageManager(flags.pkg)
-	cfg.Storage.Type = scaffold.StorageType(flags.storage)
-	cfg.Observability = scaffold.ObservabilityNone
-	if flags.observability != "" {
+	if flags.changed("pkg") && flags.pkg != "" {
+		cfg.PackageManager = scaffold.PackageManager(flags.pkg)
+	}
+	if flags.changed("storage") && flags.storage != "" {
+		cfg.Storage.Type = scaffold.StorageType(flags.storage)
+	}
+	if flags.changed("observability") && flags.observability != "" {
 		cfg.Observability = scaffold.ObservabilityProvider(flags.observability)
 	}
-	switch cfg.Observability {
-	case scaffold.ObservabilityNone, scaffold.ObservabilityGrafanaOSS:
-	default:
-		return cfg, fmt.Errorf("unsupported observability stack: %s", flags.observability)
+	if flags.changed("license") && flags.license != "" {
+		cfg.Metadata.License = scaffold.ParseLicense(flags.license)
+	}
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&cfg.Metadata.Description, flags.description},
+		{&cfg.Metadata.Author, flags.author},
+		{&cfg.Metadata.Homepage, flags.homepage},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
 	}
 
-	if cfg.Storage.Type == scaffold.StorageS3 {
-		if flags.s3Endpoint == "" || flags.s3Region == "" || flags.s3Bucket == "" || flags.s3Access == "" || flags.s3Secret == "" {
-			return cfg, errors.New("s3 storage selected: all s3 connection details are required")
+	if flags.s3Endpoint != "" || flags.s3Region != "" || flags.s3Bucket != "" || flags.s3Access != "" || flags.s3Secret != "" {
+		if cfg.Storage.S3 == nil {
+			cfg.Storage.S3 = &scaffold.S3Config{}
 		}
-		cfg.Storage.S3 = &scaffold.S3Config{
-			Endpoint:  flags.s3Endpoint,
-			Region:    flags.s3Region,
-			Bucket:    flags.s3Bucket,
-			AccessKey: flags.s3Access,
-			SecretKey: flags.s3Secret,
+		s3 := cfg.Storage.S3
+		for _, field := range []struct {
+			target *string
+			value  string
+		}{
+			{&s3.Endpoint, flags.s3Endpoint},
+			{&s3.Region, flags.s3Region},
+			{&s3.Bucket, flags.s3Bucket},
+			{&s3.AccessKey, flags.s3Access},
+			{&s3.SecretKey, flags.s3Secret},
+		} {
+			if field.value != "" {
+				*field.target = field.value
+			}
 		}
 	}
 
+	if flags.template != "" {
+		cfg.Template = flags.template
+	}
+	if len(flags.features) > 0 {
+		cfg.Features = flags.features
+	}
+	template, err := templatesource.Absolute(cfg.Template)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Template = template
+
 	if cfg.Storage.Type == scaffold.StorageLocal && cfg.Storage.Local == nil {
 		cfg.Storage.Local = &scaffold.LocalStorageConfig{Path: "storage"}
 	}
 
-	if err := validate.ProjectName(cfg.ProjectName); err != nil {
-		return cfg, err
-	}
-	if err := validate.ModulePath(cfg.ModulePath); err != nil {
+	if err := scaffold.ValidateConfig(cfg); err != nil {
 		return cfg, err
 	}
 
@@ -335,17 +678,140 @@ func configFromFlags(args []string, flags newFlags) (scaffold.ScaffoldConfigurat
 	return cfg, nil
 }
 
-func runNonInteractive(cfg scaffold.ScaffoldConfiguration) error {
+func loadPresets() ([]scaffold.Preset, error) {
+	dir, err := scaffold.DefaultPresetDir()
+	if err != nil {
+		return nil, err
+	}
+	return scaffold.LoadPresets(dir)
+}
+
+// applyPreset applies the preset called name, if any, to cfg.
+func applyPreset(cfg *scaffold.ScaffoldConfiguration, name string) error {
+	if name == "" {
+		return nil
+	}
+	presets, err := loadPresetsFn()
+	if err != nil {
+		return err
+	}
+	preset, err := scaffold.FindPreset(presets, name)
+	if err != nil {
+		return err
+	}
+	preset.Apply(cfg)
+	return nil
+}
+
+func runNonInteractive(cfg scaffold.ScaffoldConfiguration, opts runOptions) error {
+	if opts.dryRun {
+		return printPlan(cfg, opts.diffDir)
+	}
+	var err error
+	if cfg.Secrets, err = scaffold.GenerateSecrets(cfg); err != nil {
+		return err
+	}
 	nonEmpty, err := validate.IsNonEmptyDir(cfg.Destination)
 	if err != nil {
 		return err
 	}
+	var resolve scaffold.ConflictResolver
 	if nonEmpty {
-		return fmt.Errorf("destination %s is not empty", cfg.Destination)
+		if opts.onConflict == "" {
+			return fmt.Errorf("%w: %s (choose how to handle existing files with --on-conflict=skip|overwrite|backup|prompt)", scaffold.ErrDestinationNotEmpty, cfg.Destination)
+		}
+		if resolve, err = conflictResolver(cfg, opts.onConflict); err != nil {
+			return err
+		}
 	}
-	if err := scaffold.ScaffoldProject(cfg, false); err != nil {
+	steps, err := scaffold.PostGenerateSteps(cfg, opts.install, opts.verify, opts.skipSteps)
+	if err != nil {
 		return err
 	}
-	ui.PrintSummary(cfg.Destination)
+	observe := printStepEvent
+	if opts.observe != nil {
+		observe = opts.observe
+	}
+	var warnings warningLog
+	err = scaffold.ScaffoldProject(cfg, scaffold.Options{
+		Resolve: resolve,
+		Steps:   steps,
+		Observe: warnings.observe(observe),
+	})
+	return finishGeneration(cfg, opts, steps, warnings, err, ui.PrintSummary)
+}
+
+// conflictResolver turns policy into a resolver. In prompt mode every
+// conflicting file is asked about up front, before anything is written.
+func conflictResolver(cfg scaffold.ScaffoldConfiguration, policy scaffold.ConflictPolicy) (scaffold.ConflictResolver, error) {
+	if policy != scaffold.ConflictPrompt {
+		return policy.Resolver(), nil
+	}
+	source, err := templatesource.Resolve(cfg.Template, templatesource.Options{})
+	if err != nil {
+		return nil, err
+	}
+	plan, err := scaffold.PlanProject(cfg, source.FS, nil)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := scaffold.FindConflicts(plan, cfg.Destination)
+	if err != nil {
+		return nil, err
+	}
+	choices := make(map[string]scaffold.Resolution, len(conflicts))
+	for i, conflict := range conflicts {
+		resolution, err := resolveConflictFn(conflict, i+1, len(conflicts))
+		if err != nil {
+			return nil, err
+		}
+		choices[conflict.Path] = resolution
+	}
+	return scaffold.ResolverFromChoices(choices), nil
+}
+
+func printPlan(cfg scaffold.ScaffoldConfiguration, diffDir string) error {
+	source, err := templatesource.Resolve(cfg.Template, templatesource.Options{})
+	if err != nil {
+		return err
+	}
+	plan, err := scaffold.PlanProject(cfg, source.FS, nil)
+	if err != nil {
+		return err
+	}
+
+	if diffDir != "" {
+		if info, err := os.Stat(diffDir); err != nil {
+			return err
+		} else if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", diffDir)
+		}
+		diff, err := scaffold.DiffPlan(plan, diffDir)
+		if err != nil {
+			return err
+		}
+		if diff == "" {
+			fmt.Printf("No differences from %s\n", diffDir)
+			return nil
+		}
+		fmt.Print(diff)
+		return nil
+	}
+
+	entries := make([]ui.PlanEntry, 0, len(plan.Files))
+	for _, file := range plan.Files {
+		var notes []string
+		if file.Conditional {
+			notes = append(notes, "conditional")
+		}
+		if file.Generated {
+			notes = append(notes, "from .env.example")
+		}
+		if len(file.EnvOverrides) > 0 {
+			notes = append(notes, "overrides "+strings.Join(file.EnvOverrides, ", "))
+		}
+		entries = append(entries, ui.PlanEntry{Path: file.Path, Notes: notes})
+	}
+	ui.PrintPlan(cfg.Destination, entries, plan.Excluded)
 	return nil
 }
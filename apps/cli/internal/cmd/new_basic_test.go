@@ -23,7 +23,7 @@ func TestScaffoldBasicFlow(t *testing.T) {
 		"README.md.tmpl": &fstest.MapFile{Data: []byte("{{PROJECT_NAME}}")},
 	}
 
-	if err := scaffold.ScaffoldFromFS(cfg, true, fsys, nil); err != nil {
+	if err := scaffold.ScaffoldFromFS(cfg, scaffold.Options{Resolve: scaffold.ConflictOverwrite.Resolver()}, fsys, nil); err != nil {
 		t.Fatalf("scaffold failed: %v", err)
 	}
 
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
@@ -107,3 +109,135 @@ func TestConfigFromFlagsRejectsUnknownObservability(t *testing.T) {
 		t.Fatalf("expected error for unsupported observability")
 	}
 }
+
+func TestConfigFromFlagsParsesProjectMetadata(t *testing.T) {
+	flags := newFlags{
+		modulePath:  "github.com/acme/demo",
+		license:     "bsd-3",
+		description: "Demo app",
+		author:      "Acme Inc.",
+		homepage:    "https://acme.dev",
+	}
+	cfg, err := configFromFlags([]string{"demo"}, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := scaffold.ProjectMetadata{Description: "Demo app", Author: "Acme Inc.", Homepage: "https://acme.dev", License: scaffold.LicenseBSD3}
+	if cfg.Metadata != want {
+		t.Fatalf("expected %+v, got %+v", want, cfg.Metadata)
+	}
+
+	flags.license = "GPL-3.0"
+	if _, err := configFromFlags([]string{"demo"}, flags); err == nil || !strings.Contains(err.Error(), "project.license") {
+		t.Fatalf("expected unsupported license to be rejected, got %v", err)
+	}
+}
+
+func TestConfigFromFlagsParsesPorts(t *testing.T) {
+	flags := newFlags{
+		modulePath: "github.com/acme/demo",
+		apiPort:    "9090",
+		webPort:    "9090",
+	}
+	if _, err := configFromFlags([]string{"demo"}, flags); err == nil || !strings.Contains(err.Error(), "ports.web") {
+		t.Fatalf("expected clashing ports to be rejected, got %v", err)
+	}
+
+	flags.webPort = "5173"
+	cfg, err := configFromFlags([]string{"demo"}, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.APIPort != "9090" || cfg.WebPort != "5173" {
+		t.Fatalf("expected ports 9090/5173, got %s/%s", cfg.APIPort, cfg.WebPort)
+	}
+}
+
+func TestConfigFromFlagsParsesGitOptions(t *testing.T) {
+	flags := newFlags{
+		modulePath:  "github.com/acme/demo",
+		git:         true,
+		gitBranch:   "trunk",
+		gitRemote:   "git@github.com:acme/demo.git",
+		gitAuthor:   "Demo Bot",
+		gitEmail:    "bot@example.com",
+		gitMessage:  "chore: start",
+		gitNoCommit: true,
+	}
+	cfg, err := configFromFlags([]string{"demo"}, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := scaffold.GitOptions{
+		Branch:        "trunk",
+		Remote:        "git@github.com:acme/demo.git",
+		AuthorName:    "Demo Bot",
+		AuthorEmail:   "bot@example.com",
+		CommitMessage: "chore: start",
+		NoCommit:      true,
+	}
+	if cfg.Git != want {
+		t.Fatalf("expected git options %+v, got %+v", want, cfg.Git)
+	}
+
+	flags.gitBranch = "bad branch"
+	if _, err := configFromFlags([]string{"demo"}, flags); err == nil || !strings.Contains(err.Error(), "gitOptions.branch") {
+		t.Fatalf("expected the branch to be rejected, got %v", err)
+	}
+}
+
+func TestConfigFromFlagsAppliesPresetBeforeFlags(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	given := map[string]bool{"web": true}
+	flags := newFlags{
+		modulePath: "github.com/acme/demo",
+		preset:     "minimal",
+		web:        true,
+		set:        func(name string) bool { return given[name] },
+	}
+	cfg, err := configFromFlags([]string{"demo"}, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.IncludeWeb || cfg.IncludeDocker {
+		t.Fatalf("expected --web to override the minimal preset only for the web app, got web=%t docker=%t", cfg.IncludeWeb, cfg.IncludeDocker)
+	}
+
+	flags.preset = "nope"
+	if _, err := configFromFlags([]string{"demo"}, flags); err == nil || !strings.Contains(err.Error(), "unknown preset") {
+		t.Fatalf("expected an unknown preset error, got %v", err)
+	}
+}
+
+func TestConfigFromFlagsOverridesConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kickstart.yaml")
+	content := "version: 1\nname: demo\nmodule: github.com/acme/demo\nweb: false\nobservability: grafana-oss\ndatabase:\n  host: db.internal\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	given := map[string]bool{"db-host": true, "observability": true}
+	flags := newFlags{
+		configPath:    path,
+		web:           true,
+		dbHost:        "override",
+		observability: "none",
+		set:           func(name string) bool { return given[name] },
+	}
+	cfg, err := configFromFlags(nil, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProjectName != "demo" || cfg.ModulePath != "github.com/acme/demo" {
+		t.Fatalf("expected name and module from config file, got %s %s", cfg.ProjectName, cfg.ModulePath)
+	}
+	if cfg.IncludeWeb {
+		t.Fatalf("expected web from config file when --web was not given")
+	}
+	if cfg.DBConnection.Host != "override" {
+		t.Fatalf("expected --db-host to override config file, got %s", cfg.DBConnection.Host)
+	}
+	if cfg.Observability != scaffold.ObservabilityNone {
+		t.Fatalf("expected --observability to override config file, got %s", cfg.Observability)
+	}
+}
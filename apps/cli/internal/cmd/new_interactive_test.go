@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/prompts"
@@ -18,15 +22,21 @@ func restoreInteractiveDeps() {
 	databaseFlowFn = prompts.DatabaseFlow
 	storageFlowFn = prompts.StorageFlow
 	observabilityFlowFn = prompts.ObservabilityFlow
+	projectFlowFn = prompts.ProjectFlow
 	reviewConfigFn = prompts.ReviewConfig
 	validateProjectNameFn = validate.ProjectName
 	validateModulePathFn = validate.ModulePath
 	resolveProjectDestinationFn = validate.ResolveProjectDestination
 	isNonEmptyDirFn = validate.IsNonEmptyDir
 	showWelcomeFn = showWelcomeScreen
-	runWithSpinnerFn = ui.RunWithSpinner
+	runWithProgressFn = ui.RunWithProgress
 	scaffoldProjectFn = scaffold.ScaffoldProject
 	printSummaryFn = ui.PrintSummary
+	hasGitIdentityFn = scaffold.HasGitIdentity
+	gitIdentityFlowFn = prompts.GitIdentityFlow
+	loadPresetsFn = loadPresets
+	chooseConflictPolicyFn = prompts.ConflictPolicy
+	resolveConflictFn = prompts.ResolveConflict
 }
 
 func TestRunInteractiveBasicSkipsAdvancedPrompts(t *testing.T) {
@@ -37,9 +47,10 @@ func TestRunInteractiveBasicSkipsAdvancedPrompts(t *testing.T) {
 	databaseCalled := false
 	storageCalled := false
 	observabilityCalled := false
+	projectCalled := false
 
 	showWelcomeFn = func() error { return nil }
-	chooseFlowFn = func() (prompts.FlowChoice, error) { return prompts.FlowBasic, nil }
+	chooseFlowFn = func([]scaffold.Preset) (prompts.FlowChoice, string, error) { return prompts.FlowBasic, "", nil }
 	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) {
 		cfg.ProjectName = "demo"
 		cfg.ModulePath = "github.com/acme/demo"
@@ -65,6 +76,10 @@ func TestRunInteractiveBasicSkipsAdvancedPrompts(t *testing.T) {
 		observabilityCalled = true
 		return nil
 	}
+	projectFlowFn = func(cfg *scaffold.ScaffoldConfiguration) error {
+		projectCalled = true
+		return nil
+	}
 	reviewConfigFn = func(cfg scaffold.ScaffoldConfiguration) (prompts.ReviewAction, error) {
 		return prompts.ReviewGenerate, nil
 	}
@@ -72,18 +87,19 @@ func TestRunInteractiveBasicSkipsAdvancedPrompts(t *testing.T) {
 	validateModulePathFn = func(string) error { return nil }
 	resolveProjectDestinationFn = func(baseArg, projectName string) (string, error) { return "/tmp/demo", nil }
 	isNonEmptyDirFn = func(path string) (bool, error) { return false, nil }
-	runWithSpinnerFn = func(_ string, fn func() error) error { return fn() }
-	scaffoldProjectFn = func(scaffold.ScaffoldConfiguration, bool) error { return nil }
-	printSummaryFn = func(string) {}
+	runWithProgressFn = func(_ string, fn func(ui.Progress) error) error { return fn(func(ui.ProgressUpdate) {}) }
+	scaffoldProjectFn = func(scaffold.ScaffoldConfiguration, scaffold.Options) error { return nil }
+	printSummaryFn = func(ui.Summary) {}
+	hasGitIdentityFn = func(scaffold.GitOptions) bool { return true }
 
-	if err := runInteractive(); err != nil {
+	if err := runInteractive(scaffold.DefaultConfig(), runOptions{}); err != nil {
 		t.Fatalf("runInteractive returned error: %v", err)
 	}
 
 	if destinationCalled {
 		t.Fatalf("destination flow should not run in basic mode")
 	}
-	if componentsCalled || databaseCalled || storageCalled || observabilityCalled {
+	if componentsCalled || databaseCalled || storageCalled || observabilityCalled || projectCalled {
 		t.Fatalf("advanced flows should not run in basic mode")
 	}
 }
@@ -97,9 +113,9 @@ func TestRunInteractiveAdvancedFlowOrder(t *testing.T) {
 		calls = append(calls, "welcome")
 		return nil
 	}
-	chooseFlowFn = func() (prompts.FlowChoice, error) {
+	chooseFlowFn = func([]scaffold.Preset) (prompts.FlowChoice, string, error) {
 		calls = append(calls, "choose")
-		return prompts.FlowAdvanced, nil
+		return prompts.FlowAdvanced, "", nil
 	}
 	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) {
 		calls = append(calls, "basic")
@@ -107,6 +123,10 @@ func TestRunInteractiveAdvancedFlowOrder(t *testing.T) {
 		cfg.ModulePath = "github.com/acme/demo"
 		return cfg, nil
 	}
+	projectFlowFn = func(cfg *scaffold.ScaffoldConfiguration) error {
+		calls = append(calls, "project")
+		return nil
+	}
 	destinationFlowFn = func(cfg *scaffold.ScaffoldConfiguration) error {
 		calls = append(calls, "destination")
 		return nil
@@ -135,16 +155,259 @@ func TestRunInteractiveAdvancedFlowOrder(t *testing.T) {
 	validateModulePathFn = func(string) error { return nil }
 	resolveProjectDestinationFn = func(baseArg, projectName string) (string, error) { return "/tmp/demo", nil }
 	isNonEmptyDirFn = func(path string) (bool, error) { return false, nil }
-	runWithSpinnerFn = func(_ string, fn func() error) error { return fn() }
-	scaffoldProjectFn = func(scaffold.ScaffoldConfiguration, bool) error { return nil }
-	printSummaryFn = func(string) {}
+	runWithProgressFn = func(_ string, fn func(ui.Progress) error) error { return fn(func(ui.ProgressUpdate) {}) }
+	scaffoldProjectFn = func(scaffold.ScaffoldConfiguration, scaffold.Options) error { return nil }
+	printSummaryFn = func(ui.Summary) {}
+	hasGitIdentityFn = func(scaffold.GitOptions) bool { return true }
 
-	if err := runInteractive(); err != nil {
+	if err := runInteractive(scaffold.DefaultConfig(), runOptions{}); err != nil {
 		t.Fatalf("runInteractive returned error: %v", err)
 	}
 
-	want := []string{"welcome", "choose", "basic", "destination", "components", "database", "storage", "observability", "review"}
+	want := []string{"welcome", "choose", "basic", "project", "destination", "components", "database", "storage", "observability", "review"}
 	if !reflect.DeepEqual(calls, want) {
 		t.Fatalf("unexpected call order: got %v, want %v", calls, want)
 	}
 }
+
+func TestConflictResolverPromptsForEachConflict(t *testing.T) {
+	t.Cleanup(restoreInteractiveDeps)
+
+	dest := t.TempDir()
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = dest
+	for path, content := range map[string]string{"README.md": "mine\n", "apps/api/go.mod": "module mine\n"} {
+		if err := os.MkdirAll(filepath.Join(dest, filepath.Dir(path)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dest, path), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	var asked []string
+	resolveConflictFn = func(conflict scaffold.Conflict, index, total int) (scaffold.Resolution, error) {
+		asked = append(asked, conflict.Path)
+		if total != 2 || conflict.Diff() == "" {
+			t.Fatalf("unexpected conflict %d/%d: %s", index, total, conflict.Path)
+		}
+		return scaffold.ResolveWriteNew, nil
+	}
+
+	resolve, err := conflictResolver(cfg, scaffold.ConflictPrompt)
+	if err != nil {
+		t.Fatalf("conflictResolver: %v", err)
+	}
+	if want := []string{"README.md", "apps/api/go.mod"}; !reflect.DeepEqual(asked, want) {
+		t.Fatalf("expected prompts for %v, got %v", want, asked)
+	}
+	if got := resolve("README.md"); got != scaffold.ResolveWriteNew {
+		t.Fatalf("expected chosen resolution, got %s", got)
+	}
+	if got := resolve("unrelated.txt"); got != scaffold.ResolveKeep {
+		t.Fatalf("expected files that were not asked about to be kept, got %s", got)
+	}
+}
+
+func TestRunInteractiveCancelsOnNonEmptyDestination(t *testing.T) {
+	t.Cleanup(restoreInteractiveDeps)
+
+	showWelcomeFn = func() error { return nil }
+	chooseFlowFn = func([]scaffold.Preset) (prompts.FlowChoice, string, error) { return prompts.FlowBasic, "", nil }
+	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) { return cfg, nil }
+	reviewConfigFn = func(scaffold.ScaffoldConfiguration) (prompts.ReviewAction, error) { return prompts.ReviewGenerate, nil }
+	validateProjectNameFn = func(string) error { return nil }
+	validateModulePathFn = func(string) error { return nil }
+	resolveProjectDestinationFn = func(string, string) (string, error) { return "/tmp/demo", nil }
+	isNonEmptyDirFn = func(string) (bool, error) { return true, nil }
+	chooseConflictPolicyFn = func(string) (scaffold.ConflictPolicy, error) { return "", nil }
+	scaffoldProjectFn = func(scaffold.ScaffoldConfiguration, scaffold.Options) error {
+		t.Fatalf("scaffold should not run after cancelling")
+		return nil
+	}
+
+	if err := runInteractive(scaffold.DefaultConfig(), runOptions{}); err == nil || err.Error() != "cancelled" {
+		t.Fatalf("expected cancelled, got %v", err)
+	}
+}
+
+func TestRunInteractiveReportsFailedStep(t *testing.T) {
+	t.Cleanup(restoreInteractiveDeps)
+
+	saved := filepath.Join(t.TempDir(), "demo.yaml")
+	showWelcomeFn = func() error { return nil }
+	chooseFlowFn = func([]scaffold.Preset) (prompts.FlowChoice, string, error) { return prompts.FlowBasic, "", nil }
+	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) { return cfg, nil }
+	reviewConfigFn = func(scaffold.ScaffoldConfiguration) (prompts.ReviewAction, error) { return prompts.ReviewGenerate, nil }
+	validateProjectNameFn = func(string) error { return nil }
+	validateModulePathFn = func(string) error { return nil }
+	resolveProjectDestinationFn = func(string, string) (string, error) { return "/tmp/demo", nil }
+	isNonEmptyDirFn = func(string) (bool, error) { return false, nil }
+	var statuses []string
+	runWithProgressFn = func(_ string, fn func(ui.Progress) error) error {
+		return fn(func(update ui.ProgressUpdate) {
+			if update.Status != "" {
+				statuses = append(statuses, update.Status)
+			}
+		})
+	}
+	scaffoldProjectFn = func(_ scaffold.ScaffoldConfiguration, opts scaffold.Options) error {
+		if len(opts.Steps) != 4 {
+			t.Fatalf("expected the install steps, got %v", opts.Steps)
+		}
+		opts.Observe(scaffold.Event{Kind: scaffold.EventStepStart, Phase: scaffold.PhaseSteps, Step: opts.Steps[1].Name, Command: opts.Steps[1].String()})
+		return &scaffold.StepError{Step: opts.Steps[1], Err: errors.New("exit status 1")}
+	}
+	hasGitIdentityFn = func(scaffold.GitOptions) bool { return true }
+	printSummaryFn = func(ui.Summary) { t.Fatalf("summary should not be printed after a failed step") }
+
+	err := runInteractive(scaffold.DefaultConfig(), runOptions{install: true, saveConfig: saved})
+	if err == nil || !strings.Contains(err.Error(), "--skip-step install") {
+		t.Fatalf("expected a skip hint, got %v", err)
+	}
+	if want := []string{"Running `bun install`..."}; !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("expected spinner status %v, got %v", want, statuses)
+	}
+	if _, err := os.Stat(saved); err != nil {
+		t.Fatalf("expected the config to be saved for the generated project: %v", err)
+	}
+}
+
+func TestRunInteractiveAsksForMissingGitIdentity(t *testing.T) {
+	t.Cleanup(restoreInteractiveDeps)
+
+	showWelcomeFn = func() error { return nil }
+	chooseFlowFn = func([]scaffold.Preset) (prompts.FlowChoice, string, error) { return prompts.FlowBasic, "", nil }
+	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) {
+		cfg.ProjectName = "demo"
+		cfg.ModulePath = "github.com/acme/demo"
+		return cfg, nil
+	}
+	reviewConfigFn = func(scaffold.ScaffoldConfiguration) (prompts.ReviewAction, error) { return prompts.ReviewGenerate, nil }
+	resolveProjectDestinationFn = func(string, string) (string, error) { return "/tmp/demo", nil }
+	isNonEmptyDirFn = func(string) (bool, error) { return false, nil }
+	runWithProgressFn = func(_ string, fn func(ui.Progress) error) error { return fn(func(ui.ProgressUpdate) {}) }
+	hasGitIdentityFn = func(opts scaffold.GitOptions) bool { return false }
+	gitIdentityFlowFn = func(cfg *scaffold.ScaffoldConfiguration) error {
+		cfg.Git.AuthorName = "Demo Bot"
+		cfg.Git.AuthorEmail = "bot@example.com"
+		return nil
+	}
+	var generated scaffold.ScaffoldConfiguration
+	scaffoldProjectFn = func(cfg scaffold.ScaffoldConfiguration, opts scaffold.Options) error {
+		generated = cfg
+		opts.Observe(scaffold.Event{Kind: scaffold.EventWarning, Phase: scaffold.PhaseGit, Message: scaffold.NoGitIdentityWarning})
+		return nil
+	}
+	var summary ui.Summary
+	printSummaryFn = func(s ui.Summary) { summary = s }
+
+	if err := runInteractive(scaffold.DefaultConfig(), runOptions{}); err != nil {
+		t.Fatalf("runInteractive returned error: %v", err)
+	}
+	if generated.Git.AuthorName != "Demo Bot" || generated.Git.AuthorEmail != "bot@example.com" {
+		t.Fatalf("expected the prompted identity to be used, got %+v", generated.Git)
+	}
+	if !summary.Staged || len(summary.Warnings) != 1 || summary.Warnings[0] != scaffold.NoGitIdentityWarning {
+		t.Fatalf("expected the summary to report the staged files, got %+v", summary)
+	}
+}
+
+func TestRunInteractiveAppliesChosenPreset(t *testing.T) {
+	t.Cleanup(restoreInteractiveDeps)
+
+	loadPresetsFn = func() ([]scaffold.Preset, error) { return scaffold.BuiltinPresets(), nil }
+	showWelcomeFn = func() error { return nil }
+	chooseFlowFn = func(presets []scaffold.Preset) (prompts.FlowChoice, string, error) {
+		return prompts.FlowPreset, "minimal", nil
+	}
+	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) {
+		cfg.ProjectName = "demo"
+		cfg.ModulePath = "github.com/acme/demo"
+		return cfg, nil
+	}
+	componentsFlowFn = func(*scaffold.ScaffoldConfiguration) error {
+		t.Fatalf("advanced flows should not run for a preset")
+		return nil
+	}
+	reviewConfigFn = func(scaffold.ScaffoldConfiguration) (prompts.ReviewAction, error) { return prompts.ReviewGenerate, nil }
+	resolveProjectDestinationFn = func(string, string) (string, error) { return "/tmp/demo", nil }
+	isNonEmptyDirFn = func(string) (bool, error) { return false, nil }
+	runWithProgressFn = func(_ string, fn func(ui.Progress) error) error { return fn(func(ui.ProgressUpdate) {}) }
+	hasGitIdentityFn = func(scaffold.GitOptions) bool { return true }
+	var generated scaffold.ScaffoldConfiguration
+	scaffoldProjectFn = func(cfg scaffold.ScaffoldConfiguration, _ scaffold.Options) error {
+		generated = cfg
+		return nil
+	}
+	printSummaryFn = func(ui.Summary) {}
+
+	if err := runInteractive(scaffold.DefaultConfig(), runOptions{}); err != nil {
+		t.Fatalf("runInteractive returned error: %v", err)
+	}
+	if generated.IncludeWeb || generated.IncludeDocker || generated.ProjectName != "demo" {
+		t.Fatalf("expected the minimal preset with the prompted name, got %+v", generated)
+	}
+}
+
+func TestRunInteractiveEditsOneSectionFromReview(t *testing.T) {
+	t.Cleanup(restoreInteractiveDeps)
+
+	var calls []string
+	record := func(name string) func(*scaffold.ScaffoldConfiguration) error {
+		return func(*scaffold.ScaffoldConfiguration) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	showWelcomeFn = func() error { return nil }
+	chooseFlowFn = func([]scaffold.Preset) (prompts.FlowChoice, string, error) {
+		calls = append(calls, "choose")
+		return prompts.FlowBasic, "", nil
+	}
+	basicFlowFn = func(cfg scaffold.ScaffoldConfiguration) (scaffold.ScaffoldConfiguration, error) {
+		calls = append(calls, "basic")
+		cfg.ProjectName = "demo"
+		cfg.ModulePath = "github.com/acme/demo"
+		return cfg, nil
+	}
+	destinationFlowFn = record("destination")
+	componentsFlowFn = record("components")
+	databaseFlowFn = func(cfg *scaffold.ScaffoldConfiguration) error {
+		calls = append(calls, "database")
+		scaffold.SetDatabaseType(cfg, scaffold.DatabaseMySQL)
+		return nil
+	}
+	storageFlowFn = record("storage")
+	observabilityFlowFn = record("observability")
+	projectFlowFn = record("project")
+	actions := []prompts.ReviewAction{prompts.ReviewEditDatabase, prompts.ReviewGenerate}
+	reviewConfigFn = func(scaffold.ScaffoldConfiguration) (prompts.ReviewAction, error) {
+		calls = append(calls, "review")
+		action := actions[0]
+		actions = actions[1:]
+		return action, nil
+	}
+	resolveProjectDestinationFn = func(string, string) (string, error) { return "/tmp/demo", nil }
+	isNonEmptyDirFn = func(string) (bool, error) { return false, nil }
+	runWithProgressFn = func(_ string, fn func(ui.Progress) error) error { return fn(func(ui.ProgressUpdate) {}) }
+	hasGitIdentityFn = func(scaffold.GitOptions) bool { return true }
+	var generated scaffold.ScaffoldConfiguration
+	scaffoldProjectFn = func(cfg scaffold.ScaffoldConfiguration, _ scaffold.Options) error {
+		generated = cfg
+		return nil
+	}
+	printSummaryFn = func(ui.Summary) {}
+
+	if err := runInteractive(scaffold.DefaultConfig(), runOptions{}); err != nil {
+		t.Fatalf("runInteractive returned error: %v", err)
+	}
+	if want := []string{"choose", "basic", "review", "database", "review"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected only the database flow to run again, got %v", calls)
+	}
+	if generated.DatabaseType != scaffold.DatabaseMySQL || generated.ProjectName != "demo" {
+		t.Fatalf("expected the edit to keep the other answers, got %+v", generated)
+	}
+}
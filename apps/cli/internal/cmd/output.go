@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+)
+
+// Output formats of `gokickstart new`.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// jsonResult is the last line of `new --output json`.
+type jsonResult struct {
+	Event       string `json:"event"`
+	OK          bool   `json:"ok"`
+	Destination string `json:"destination,omitempty"`
+	// Files lists the generated files written to the destination. Existing
+	// files the conflict policy kept are listed in Conflicts instead.
+	Files     []string                       `json:"files"`
+	Conflicts map[string]scaffold.Resolution `json:"conflicts,omitempty"`
+	Secrets   []scaffold.GeneratedSecret     `json:"secrets,omitempty"`
+	Warnings  []string                       `json:"warnings,omitempty"`
+	Error     *jsonError                     `json:"error,omitempty"`
+}
+
+type jsonError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Step names the failed post-generation step.
+	Step string `json:"step,omitempty"`
+}
+
+// jsonStream writes generation events as NDJSON and collects the result.
+type jsonStream struct {
+	enc       *json.Encoder
+	files     []string
+	seen      map[string]bool
+	conflicts map[string]scaffold.Resolution
+	warnings  []string
+}
+
+func newJSONStream(w io.Writer) *jsonStream {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonStream{enc: enc, seen: map[string]bool{}, conflicts: map[string]scaffold.Resolution{}}
+}
+
+func (s *jsonStream) observe(event scaffold.Event) {
+	if event.Kind == scaffold.EventWarning {
+		s.warnings = append(s.warnings, event.Message)
+	}
+	if event.Kind == scaffold.EventFile {
+		switch {
+		case event.Phase != scaffold.PhasePlace:
+			if !s.seen[event.Path] {
+				s.seen[event.Path] = true
+				s.files = append(s.files, event.Path)
+			}
+		case event.Resolution != scaffold.ResolveReplace:
+			s.conflicts[event.Path] = event.Resolution
+		}
+	}
+	_ = s.enc.Encode(event)
+}
+
+// finish writes the result for cfg. code classifies err when it is set, and
+// is derived from err otherwise.
+func (s *jsonStream) finish(cfg scaffold.ScaffoldConfiguration, code string, err error) {
+	result := jsonResult{Event: "result", OK: err == nil, Destination: cfg.Destination, Files: []string{}}
+	if err == nil || scaffold.ErrorCode(err) == scaffold.CodeStepFailed {
+		// A failed step still leaves a generated project behind.
+		for _, path := range s.files {
+			if resolution, ok := s.conflicts[path]; !ok || resolution == scaffold.ResolveBackup {
+				result.Files = append(result.Files, path)
+			}
+		}
+		if len(s.conflicts) > 0 {
+			result.Conflicts = s.conflicts
+		}
+		result.Secrets = cfg.Secrets.Generated(cfg)
+		result.Warnings = s.warnings
+	}
+	if err != nil {
+		if code == "" {
+			code = scaffold.ErrorCode(err)
+		}
+		result.Error = &jsonError{Code: code, Message: err.Error()}
+		var stepErr *scaffold.StepError
+		if errors.As(err, &stepErr) {
+			result.Error.Step = stepErr.Step.Name
+		}
+	}
+	_ = s.enc.Encode(result)
+}
+
+// warningLog collects the warning events of a generation for the summary.
+type warningLog []scaffold.Event
+
+// observe records warnings and passes every event on to next.
+func (w *warningLog) observe(next func(scaffold.Event)) func(scaffold.Event) {
+	return func(event scaffold.Event) {
+		if event.Kind == scaffold.EventWarning {
+			*w = append(*w, event)
+		}
+		next(event)
+	}
+}
+
+func (w warningLog) has(phase scaffold.Phase) bool {
+	for _, event := range w {
+		if event.Phase == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// progressObserver shows the generation events in the spinner.
+func progressObserver(progress ui.Progress) func(scaffold.Event) {
+	return func(event scaffold.Event) {
+		switch event.Kind {
+		case scaffold.EventPhaseStart:
+			progress(ui.ProgressUpdate{Status: ui.PhaseLabel(string(event.Phase))})
+		case scaffold.EventFile:
+			if event.Done > 0 {
+				progress(ui.ProgressUpdate{Done: event.Done, Total: event.Total})
+			}
+		case scaffold.EventStepStart:
+			progress(ui.ProgressUpdate{Status: fmt.Sprintf("Running `%s`...", event.Command)})
+		case scaffold.EventStepOutput:
+			progress(ui.ProgressUpdate{Line: event.Line})
+		}
+	}
+}
+
+// printStepEvent prints the post-generation steps when no spinner is running.
+func printStepEvent(event scaffold.Event) {
+	switch event.Kind {
+	case scaffold.EventStepStart:
+		ui.PrintStep(event.Command)
+	case scaffold.EventStepOutput:
+		ui.PrintStepOutput(event.Line)
+	}
+}
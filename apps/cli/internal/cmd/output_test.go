@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+)
+
+func TestJSONStreamWritesEventsAndResult(t *testing.T) {
+	var out bytes.Buffer
+	stream := newJSONStream(&out)
+	for _, event := range []scaffold.Event{
+		{Kind: scaffold.EventPhaseStart, Phase: scaffold.PhaseRender},
+		{Kind: scaffold.EventFile, Phase: scaffold.PhaseRender, Path: "README.md", Done: 1, Total: 3},
+		{Kind: scaffold.EventFile, Phase: scaffold.PhaseRender, Path: "apps/web/.env", Done: 2, Total: 3},
+		{Kind: scaffold.EventFile, Phase: scaffold.PhaseRender, Path: "notes.txt", Done: 3, Total: 3},
+		{Kind: scaffold.EventFile, Phase: scaffold.PhaseEnv, Path: "apps/web/.env", Done: 1},
+		{Kind: scaffold.EventFile, Phase: scaffold.PhasePlace, Path: "notes.txt", Resolution: scaffold.ResolveKeep},
+		{Kind: scaffold.EventFile, Phase: scaffold.PhasePlace, Path: "README.md", Resolution: scaffold.ResolveReplace},
+	} {
+		stream.observe(event)
+	}
+	cfg := scaffold.DefaultConfig()
+	cfg.Destination = "/tmp/demo"
+	cfg.Secrets = scaffold.Secrets{AuthSecretKey: "secret"}
+	stepErr := &scaffold.StepError{Step: scaffold.Step{Name: scaffold.StepInstall}, Err: errors.New("exit status 1")}
+	stream.finish(cfg, "", stepErr)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected 7 events and a result, got %d lines:\n%s", len(lines), out.String())
+	}
+	var event map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if event["event"] != "file" || event["phase"] != "render" || event["total"] != float64(3) {
+		t.Fatalf("unexpected file event: %v", event)
+	}
+
+	var result jsonResult
+	if err := json.Unmarshal([]byte(lines[7]), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.Event != "result" || result.OK || result.Destination != "/tmp/demo" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if want := []string{"README.md", "apps/web/.env"}; !reflect.DeepEqual(result.Files, want) {
+		t.Fatalf("expected files %v, got %v", want, result.Files)
+	}
+	if result.Conflicts["notes.txt"] != scaffold.ResolveKeep || len(result.Conflicts) != 1 {
+		t.Fatalf("expected the kept file as the only conflict, got %v", result.Conflicts)
+	}
+	if result.Error == nil || result.Error.Code != scaffold.CodeStepFailed || result.Error.Step != scaffold.StepInstall {
+		t.Fatalf("expected a step_failed error for install, got %+v", result.Error)
+	}
+	if len(result.Secrets) != 1 || result.Secrets[0].Key != "API_AUTH.SECRET_KEY" {
+		t.Fatalf("expected the generated secret, got %+v", result.Secrets)
+	}
+}
+
+func TestJSONStreamReportsConfigErrors(t *testing.T) {
+	var out bytes.Buffer
+	stream := newJSONStream(&out)
+	stream.finish(scaffold.ScaffoldConfiguration{}, scaffold.CodeInvalidConfig, errors.New("module path is required (--module)"))
+
+	var result jsonResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.OK || result.Error == nil || result.Error.Code != scaffold.CodeInvalidConfig || len(result.Files) != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
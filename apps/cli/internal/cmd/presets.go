@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	presetsCmd := &cobra.Command{
+		Use:   "presets",
+		Short: "List the built-in and user presets for `new --preset`",
+		Long: "List the presets `gokickstart new --preset` accepts. User presets are the YAML\n" +
+			"config files in ~/.config/gokickstart/presets (or $XDG_CONFIG_HOME/gokickstart/presets);\n" +
+			"save one with `new --save-preset <name>`.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPresets()
+		},
+	}
+	rootCmd.AddCommand(presetsCmd)
+}
+
+func runPresets() error {
+	presets, err := loadPresetsFn()
+	if err != nil {
+		return err
+	}
+	rows := make([]ui.InfoRow, 0, len(presets))
+	for _, preset := range presets {
+		value := preset.Description
+		if preset.Path != "" {
+			value += " (" + preset.Path + ")"
+		}
+		rows = append(rows, ui.InfoRow{Label: preset.Name, Value: strings.TrimSpace(value)})
+	}
+	ui.PrintInfo("Presets", rows, nil, nil)
+	return nil
+}
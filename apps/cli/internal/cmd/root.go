@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/version"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +15,7 @@ var rootCmd = &cobra.Command{
 	Version: Version,
 }
 
-const Version = "0.1.0"
+const Version = version.Version
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/templatesource"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var templateLintJSON bool
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work on project templates",
+}
+
+func init() {
+	lintCmd := &cobra.Command{
+		Use:   "lint [template]",
+		Short: "Render a template for every option combination and check the output",
+		Long: "Render the template (the embedded one, or any template source accepted by\n" +
+			"`new --template`) in memory for every combination of web, docker, git, database,\n" +
+			"package manager, storage and observability. Each render is checked for unbalanced\n" +
+			"condition markers and leftover {{...}} tokens, and every generated Go package is\n" +
+			"parsed and type-checked against the generated go.mod. Third-party packages\n" +
+			"required in go.mod are not downloaded; their use is not checked.",
+		Args: cobra.MaximumNArgs(1),
+		// Findings are reported by the command itself, not usage errors.
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spec := ""
+			if len(args) > 0 {
+				spec = args[0]
+			}
+			return runTemplateLint(spec, templateLintJSON)
+		},
+	}
+	lintCmd.Flags().BoolVar(&templateLintJSON, "json", false, "print the report as JSON")
+	templateCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(templateCmd)
+}
+
+func runTemplateLint(spec string, asJSON bool) error {
+	source, err := templatesource.Resolve(spec, templatesource.Options{})
+	if err != nil {
+		return err
+	}
+	report, err := scaffold.LintTemplate(source.FS)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		issues := make([]ui.LintIssue, len(report.Findings))
+		for i, finding := range report.Findings {
+			issues[i] = ui.LintIssue{Location: finding.File, Message: finding.Message}
+			if finding.Line > 0 {
+				issues[i].Location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+			}
+			switch {
+			case finding.Combinations > 1:
+				issues[i].Combination = fmt.Sprintf("%s (and %d more)", finding.Combination, finding.Combinations-1)
+			case finding.Combinations == 1:
+				issues[i].Combination = finding.Combination
+			}
+		}
+		summary := fmt.Sprintf("%d combinations rendered, %d problem(s)", report.Combinations, len(report.Findings))
+		ui.PrintLint("🔎 Template lint: "+source.Describe(), issues, summary)
+	}
+	if len(report.Findings) > 0 {
+		return fmt.Errorf("template lint found %d problem(s)", len(report.Findings))
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTemplateLintFailsOnFindings(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md.tmpl"), []byte("# {{PROJECT_NAME}}\n{{IF web}}\nweb\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	err := runTemplateLint(dir, true)
+	if err == nil || !strings.Contains(err.Error(), "found 1 problem(s)") {
+		t.Fatalf("expected one problem, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md.tmpl"), []byte("# {{PROJECT_NAME}}\n{{IF web}}\nweb\n{{END}}\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := runTemplateLint(dir, true); err != nil {
+		t.Fatalf("expected a clean template, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/templatesource"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/upgrade"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeTemplate     string
+	upgradeBaseTemplate string
+)
+
+func init() {
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade [path]",
+		Short: "Bring template improvements into an existing generated project",
+		Long: "Re-render the current template with the project's recorded configuration and\n" +
+			"three-way merge it with the originally generated files and your working tree.\n\n" +
+			"Files you never touched are updated in place. Files changed on both sides are merged;\n" +
+			"conflicts are marked with <<<<<<< / >>>>>>> or, when no merge base is available,\n" +
+			"the new template version is written next to the file as <file>.rej.\n\n" +
+			"The merge base is read from the git commit that last wrote .gokickstart/manifest.json,\n" +
+			"or rendered from --base-template (the template of the CLI release that generated\n" +
+			"the project).\n\n" +
+			"The template recorded at generation is used unless --template selects another one.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			template := (*string)(nil)
+			if cmd.Flags().Changed("template") {
+				template = &upgradeTemplate
+			}
+			return runUpgrade(path, template, upgradeBaseTemplate)
+		},
+	}
+	upgradeCmd.Flags().StringVar(&upgradeTemplate, "template", "", "template to upgrade to (directory, .tar.gz/.zip pack or path#ref); empty selects the embedded template")
+	upgradeCmd.Flags().StringVar(&upgradeBaseTemplate, "base-template", "", "template the project was originally generated from (directory, pack or path#ref)")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+// runUpgrade upgrades the project at path. A nil template keeps the template
+// source recorded in the manifest.
+func runUpgrade(path string, template *string, baseTemplate string) error {
+	root, err := scaffold.FindManifestRoot(path)
+	if err != nil {
+		return err
+	}
+	opts := upgrade.Options{}
+	if template != nil {
+		spec, err := templatesource.Absolute(*template)
+		if err != nil {
+			return err
+		}
+		source, err := templatesource.Resolve(spec, templatesource.Options{})
+		if err != nil {
+			return err
+		}
+		opts.Template = source.FS
+		opts.TemplateSpec = spec
+	}
+	if baseTemplate != "" {
+		source, err := templatesource.Resolve(baseTemplate, templatesource.Options{})
+		if err != nil {
+			return fmt.Errorf("base template: %w", err)
+		}
+		opts.BaseTemplate = source.FS
+	}
+
+	result, err := upgrade.Upgrade(root, opts)
+	if err != nil {
+		return err
+	}
+	if result.UpToDate() {
+		ui.PrintUpgradeSummary("✅ Project already uses the current template", nil, nil)
+		return nil
+	}
+
+	title := fmt.Sprintf("✅ Upgraded from gokickstart %s to %s", result.FromVersion, result.ToVersion)
+	ui.PrintUpgradeSummary(title, changeGroups(result), changeHints(result))
+	return nil
+}
+
+func changeGroups(result upgrade.Result) []ui.ChangeGroup {
+	return []ui.ChangeGroup{
+		{Marker: "~", Label: "Updated", Paths: result.Paths(upgrade.ActionUpdated)},
+		{Marker: "+", Label: "Added", Paths: result.Paths(upgrade.ActionAdded)},
+		{Marker: "-", Label: "Removed", Paths: result.Paths(upgrade.ActionRemoved)},
+		{Marker: "≈", Label: "Merged with your changes", Paths: result.Paths(upgrade.ActionMerged)},
+		{Marker: "!", Label: "Conflicts (resolve the markers)", Paths: result.Paths(upgrade.ActionConflict)},
+		{Marker: "!", Label: "Rejected (see .rej files)", Paths: result.Paths(upgrade.ActionRejected)},
+		{Marker: "=", Label: "Kept (removed from template, but changed locally)", Paths: result.Paths(upgrade.ActionKept)},
+		{Marker: "·", Label: "Skipped (deleted locally)", Paths: result.Paths(upgrade.ActionSkipped)},
+	}
+}
+
+// changeHints lists what is left to do after result was applied; steps come
+// before the final review.
+func changeHints(result upgrade.Result, steps ...string) []string {
+	var hints []string
+	if len(result.Paths(upgrade.ActionConflict)) > 0 {
+		hints = append(hints, "resolve the conflict markers in the files listed above")
+	}
+	if len(result.Paths(upgrade.ActionRejected)) > 0 {
+		hints = append(hints, "apply or discard each `.rej` file, then delete it")
+	}
+	hints = append(hints, steps...)
+	return append(hints, "review the changes with `git diff` and commit them")
+}
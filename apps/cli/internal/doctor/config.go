@@ -0,0 +1,274 @@
+package doctor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiEnvPrefix is the prefix config.LoadConfig strips from environment keys.
+const apiEnvPrefix = "API_"
+
+type valueKind string
+
+const (
+	kindString   valueKind = "string"
+	kindInt      valueKind = "int"
+	kindBool     valueKind = "bool"
+	kindDuration valueKind = "duration"
+	kindList     valueKind = "list"
+)
+
+// configRule is one leaf field of config.Config.
+type configRule struct {
+	key      string
+	kind     valueKind
+	validate []string
+	// section is the key prefix of the optional (pointer) section the field
+	// belongs to. The API only builds that section when one of its keys is set.
+	section string
+}
+
+// loadConfigRules reads the Config struct from the Go sources in dir and
+// flattens it into one rule per koanf key.
+func loadConfigRules(dir string) ([]configRule, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]ast.Expr{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				types[typeSpec.Name.Name] = typeSpec.Type
+			}
+		}
+	}
+	root, ok := types["Config"].(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("no Config struct in %s", dir)
+	}
+	var rules []configRule
+	flattenConfig(types, root, "", "", &rules)
+	return rules, nil
+}
+
+func flattenConfig(types map[string]ast.Expr, st *ast.StructType, prefix, section string, rules *[]configRule) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tagValue, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tag := reflect.StructTag(tagValue)
+		name := tag.Get("koanf")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		fieldType := field.Type
+		fieldSection := section
+		if star, ok := fieldType.(*ast.StarExpr); ok {
+			fieldType = star.X
+			if fieldSection == "" {
+				fieldSection = key
+			}
+		}
+		if nested := structType(types, fieldType); nested != nil {
+			flattenConfig(types, nested, key+".", fieldSection, rules)
+			continue
+		}
+		var validate []string
+		if v := tag.Get("validate"); v != "" {
+			validate = strings.Split(v, ",")
+		}
+		*rules = append(*rules, configRule{
+			key:      apiEnvPrefix + strings.ToUpper(key),
+			kind:     kindOf(types, fieldType),
+			validate: validate,
+			section:  sectionKey(fieldSection),
+		})
+	}
+}
+
+func sectionKey(section string) string {
+	if section == "" {
+		return ""
+	}
+	return apiEnvPrefix + strings.ToUpper(section) + "."
+}
+
+func structType(types map[string]ast.Expr, expr ast.Expr) *ast.StructType {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t
+	case *ast.Ident:
+		if underlying, ok := types[t.Name]; ok {
+			return structType(types, underlying)
+		}
+	}
+	return nil
+}
+
+func kindOf(types map[string]ast.Expr, expr ast.Expr) valueKind {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "bool":
+			return kindBool
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return kindInt
+		}
+		if underlying, ok := types[t.Name]; ok {
+			return kindOf(types, underlying)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Duration" {
+			return kindDuration
+		}
+	case *ast.ArrayType:
+		return kindList
+	}
+	return kindString
+}
+
+// check returns what is wrong with the rule's value, or "" if it is valid.
+func (r configRule) check(values map[string]string) string {
+	if r.section != "" && !anyKeyWithPrefix(values, r.section) {
+		return ""
+	}
+	value := values[r.key]
+	if value != "" {
+		if problem := r.parse(value); problem != "" {
+			return problem
+		}
+	}
+	for _, rule := range r.validate {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if r.isZero(value) {
+				if r.kind == kindBool {
+					return "must be true (the API treats false as unset)"
+				}
+				return "is required"
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			if !slices.Contains(options, value) {
+				return fmt.Sprintf("must be one of %s, got %q", strings.Join(options, ", "), value)
+			}
+		case "email":
+			if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+				return fmt.Sprintf("must be an email address, got %q", value)
+			}
+		case "min":
+			if problem := r.checkMin(value, param); problem != "" {
+				return problem
+			}
+		}
+	}
+	return ""
+}
+
+func (r configRule) parse(value string) string {
+	switch r.kind {
+	case kindInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Sprintf("must be a whole number, got %q", value)
+		}
+	case kindBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("must be true or false, got %q", value)
+		}
+	case kindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Sprintf("must be a duration such as 30s or 5m, got %q", value)
+		}
+	}
+	return ""
+}
+
+func (r configRule) isZero(value string) bool {
+	switch r.kind {
+	case kindInt:
+		n, _ := strconv.Atoi(value)
+		return n == 0
+	case kindBool:
+		b, _ := strconv.ParseBool(value)
+		return !b
+	case kindDuration:
+		d, _ := time.ParseDuration(value)
+		return d == 0
+	case kindList:
+		return strings.Trim(value, ", ") == ""
+	}
+	return value == ""
+}
+
+func (r configRule) checkMin(value, param string) string {
+	switch r.kind {
+	case kindDuration:
+		minimum, err := time.ParseDuration(param)
+		if err != nil {
+			return ""
+		}
+		if d, _ := time.ParseDuration(value); d < minimum {
+			return fmt.Sprintf("must be at least %s", param)
+		}
+	case kindInt:
+		minimum, err := strconv.Atoi(param)
+		if err != nil {
+			return ""
+		}
+		if n, _ := strconv.Atoi(value); n < minimum {
+			return fmt.Sprintf("must be at least %s", param)
+		}
+	case kindString:
+		minimum, err := strconv.Atoi(param)
+		if err != nil {
+			return ""
+		}
+		if len(value) < minimum {
+			return fmt.Sprintf("must be at least %s characters", param)
+		}
+	}
+	return ""
+}
+
+func anyKeyWithPrefix(values map[string]string, prefix string) bool {
+	for key := range values {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
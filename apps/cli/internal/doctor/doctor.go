@@ -0,0 +1,420 @@
+// Package doctor checks that a machine can run a generated project: the
+// toolchains its package.json and go.mod ask for, Docker, free ports for the
+// compose stack and, inside a project, the .env files.
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"gopkg.in/yaml.v3"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+const (
+	GroupToolchain   = "Toolchain"
+	GroupDocker      = "Docker"
+	GroupPorts       = "Ports"
+	GroupEnvironment = "Environment"
+)
+
+// Check is one line of the report. Fix is set for warnings and failures.
+type Check struct {
+	Group  string `json:"group"`
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type Report struct {
+	// Root is the generated project that was checked, empty outside one.
+	Root   string  `json:"root,omitempty"`
+	Checks []Check `json:"checks"`
+}
+
+func (r Report) Count(status Status) int {
+	n := 0
+	for _, check := range r.Checks {
+		if check.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+type Options struct {
+	// Root is the generated project on disk. Outside a project it is empty and
+	// the environment checks are skipped.
+	Root string
+	// Files holds the project files: the project itself, or the files the
+	// default template would generate.
+	Files fs.FS
+	// Exec runs a command and returns its trimmed output.
+	Exec func(name string, args ...string) (string, error)
+	// PortFree reports whether a TCP port can be bound.
+	PortFree func(port int) bool
+}
+
+func Run(opts Options) Report {
+	if opts.Exec == nil {
+		opts.Exec = execCommand
+	}
+	if opts.PortFree == nil {
+		opts.PortFree = portFree
+	}
+	// Outside a project Docker may not end up being used, so its problems are
+	// only warnings.
+	severity := StatusWarn
+	if opts.Root != "" {
+		severity = StatusFail
+	}
+
+	report := Report{Root: opts.Root}
+	report.Checks = append(report.Checks, checkToolchain(opts)...)
+	if compose, err := fs.ReadFile(opts.Files, "docker-compose.yml"); err == nil {
+		report.Checks = append(report.Checks, checkDocker(opts, severity)...)
+		compose = interpolateCompose(compose, composeEnv(opts.Files))
+		report.Checks = append(report.Checks, checkPorts(opts, compose, severity)...)
+	}
+	if opts.Root != "" {
+		report.Checks = append(report.Checks, checkEnvironment(opts.Root)...)
+	}
+	return report
+}
+
+func execCommand(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = ln.Close()
+	return true
+}
+
+type packageJSON struct {
+	PackageManager string            `json:"packageManager"`
+	Engines        map[string]string `json:"engines"`
+}
+
+var packageManagerInstall = map[string]string{
+	"bun":  "curl -fsSL https://bun.sh/install | bash",
+	"npm":  "install Node.js from https://nodejs.org (npm ships with it)",
+	"pnpm": "corepack enable pnpm",
+	"yarn": "corepack enable yarn",
+}
+
+func checkToolchain(opts Options) []Check {
+	var checks []Check
+	if required := requiredGoVersion(opts.Files); required != "" {
+		checks = append(checks, checkGo(opts, required))
+	}
+
+	data, err := fs.ReadFile(opts.Files, "package.json")
+	if err != nil {
+		return checks
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return append(checks, Check{
+			Group:  GroupToolchain,
+			Name:   "package.json",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("cannot parse package.json: %v", err),
+			Fix:    "fix the JSON syntax in package.json",
+		})
+	}
+	manager, managerVersion, _ := strings.Cut(pkg.PackageManager, "@")
+	if manager != "" {
+		checks = append(checks, checkTool(opts, manager, managerVersion, StatusFail, packageManagerInstall[manager]))
+	}
+	if constraint := pkg.Engines["node"]; constraint != "" {
+		// bun runs the scripts itself, so node only matters for editor tooling.
+		severity := StatusFail
+		if manager == "bun" {
+			severity = StatusWarn
+		}
+		checks = append(checks, checkTool(opts, "node", strings.TrimPrefix(strings.TrimSpace(constraint), ">="), severity, "install Node.js from https://nodejs.org"))
+	}
+	return checks
+}
+
+var goDirectiveRe = regexp.MustCompile(`(?m)^go\s+(\S+)`)
+
+// requiredGoVersion returns the highest go directive of the go.mod files.
+func requiredGoVersion(files fs.FS) string {
+	required := ""
+	_ = fs.WalkDir(files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && (d.Name() == "node_modules" || d.Name() == ".git") {
+			return fs.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		data, err := fs.ReadFile(files, p)
+		if err != nil {
+			return nil
+		}
+		if match := goDirectiveRe.FindSubmatch(data); match != nil && compareVersions(string(match[1]), required) > 0 {
+			required = string(match[1])
+		}
+		return nil
+	})
+	return required
+}
+
+func checkGo(opts Options, required string) Check {
+	check := Check{Group: GroupToolchain, Name: "go"}
+	fix := fmt.Sprintf("install Go %s or newer from https://go.dev/dl/", required)
+	out, err := opts.Exec("go", "env", "GOVERSION")
+	if err != nil || out == "" {
+		check.Status, check.Detail, check.Fix = StatusFail, "not found on PATH", fix
+		return check
+	}
+	installed := strings.TrimPrefix(out, "go")
+	if compareVersions(installed, required) < 0 {
+		check.Status, check.Detail, check.Fix = StatusFail, fmt.Sprintf("%s installed, go.mod requires %s", installed, required), fix
+		return check
+	}
+	check.Status, check.Detail = StatusPass, fmt.Sprintf("%s (requires %s)", installed, required)
+	return check
+}
+
+var versionRe = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// checkTool runs `<name> --version` and compares it against the minimum
+// version, if any.
+func checkTool(opts Options, name, minimum string, severity Status, install string) Check {
+	check := Check{Group: GroupToolchain, Name: name}
+	out, err := opts.Exec(name, "--version")
+	installed := versionRe.FindString(out)
+	if err != nil || installed == "" {
+		check.Status, check.Detail, check.Fix = severity, "not found on PATH", install
+		return check
+	}
+	if minimum != "" && compareVersions(installed, minimum) < 0 {
+		check.Status = severity
+		check.Detail = fmt.Sprintf("%s installed, project requires %s or newer", installed, minimum)
+		check.Fix = fmt.Sprintf("upgrade %s to %s or newer", name, minimum)
+		return check
+	}
+	check.Status, check.Detail = StatusPass, installed
+	if minimum != "" {
+		check.Detail += fmt.Sprintf(" (requires %s)", minimum)
+	}
+	return check
+}
+
+func checkDocker(opts Options, severity Status) []Check {
+	version, err := opts.Exec("docker", "--version")
+	if err != nil {
+		return []Check{{
+			Group:  GroupDocker,
+			Name:   "docker",
+			Status: severity,
+			Detail: "not found on PATH",
+			Fix:    "install Docker from https://docs.docker.com/get-docker/",
+		}}
+	}
+	checks := []Check{{Group: GroupDocker, Name: "docker", Status: StatusPass, Detail: strings.TrimPrefix(version, "Docker version ")}}
+
+	if server, err := opts.Exec("docker", "info", "--format", "{{.ServerVersion}}"); err != nil || server == "" {
+		checks = append(checks, Check{
+			Group:  GroupDocker,
+			Name:   "daemon",
+			Status: severity,
+			Detail: "the Docker daemon is not reachable",
+			Fix:    "start Docker Desktop, or run `sudo systemctl start docker`",
+		})
+	} else {
+		checks = append(checks, Check{Group: GroupDocker, Name: "daemon", Status: StatusPass, Detail: "running (server " + server + ")"})
+	}
+
+	if compose, err := opts.Exec("docker", "compose", "version", "--short"); err != nil || compose == "" {
+		checks = append(checks, Check{
+			Group:  GroupDocker,
+			Name:   "compose",
+			Status: severity,
+			Detail: "`docker compose` is not available",
+			Fix:    "install the Docker Compose v2 plugin: https://docs.docker.com/compose/install/",
+		})
+	} else {
+		checks = append(checks, Check{Group: GroupDocker, Name: "compose", Status: StatusPass, Detail: compose})
+	}
+	return checks
+}
+
+type composePort struct {
+	port    int
+	service string
+}
+
+func checkPorts(opts Options, compose []byte, severity Status) []Check {
+	ports, err := composePorts(compose)
+	if err != nil {
+		return []Check{{
+			Group:  GroupPorts,
+			Name:   "docker-compose.yml",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("cannot parse docker-compose.yml: %v", err),
+			Fix:    "fix the YAML syntax in docker-compose.yml",
+		}}
+	}
+	var checks []Check
+	for _, p := range ports {
+		check := Check{Group: GroupPorts, Name: strconv.Itoa(p.port), Status: StatusPass, Detail: "free for " + p.service}
+		if !opts.PortFree(p.port) {
+			check.Status = severity
+			check.Detail = fmt.Sprintf("in use, needed by %s", p.service)
+			check.Fix = fmt.Sprintf("stop whatever listens on %d (`lsof -i :%d`) or change its host port in docker-compose.yml; if this project's stack is already up, run `docker compose down` first", p.port, p.port)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// composePorts lists the published host ports of a compose file, ordered by
+// port.
+func composePorts(data []byte) ([]composePort, error) {
+	var compose struct {
+		Services map[string]struct {
+			Ports []yaml.Node `yaml:"ports"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, err
+	}
+	seen := map[int]bool{}
+	var ports []composePort
+	for service, def := range compose.Services {
+		for _, node := range def.Ports {
+			var published string
+			switch node.Kind {
+			case yaml.ScalarNode:
+				published = shortSyntaxHostPort(node.Value)
+			case yaml.MappingNode:
+				var long struct {
+					Published string `yaml:"published"`
+				}
+				if err := node.Decode(&long); err != nil {
+					return nil, err
+				}
+				published = long.Published
+			}
+			port, err := strconv.Atoi(published)
+			if err != nil || seen[port] {
+				continue
+			}
+			seen[port] = true
+			ports = append(ports, composePort{port: port, service: service})
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].port < ports[j].port })
+	return ports, nil
+}
+
+var composeVarRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}`)
+
+// composeEnv reads the variables Compose interpolates from .env, or from
+// .env.example when .env has not been created yet.
+func composeEnv(files fs.FS) map[string]string {
+	for _, name := range []string{".env", ".env.example"} {
+		if data, err := fs.ReadFile(files, name); err == nil {
+			_, values := scaffold.ParseEnv(data)
+			return values
+		}
+	}
+	return map[string]string{}
+}
+
+// interpolateCompose expands ${VAR}, ${VAR:-default} and ${VAR:?error} the way
+// docker compose does, so published ports read from .env can be checked.
+// Variables without a value or default expand to nothing.
+func interpolateCompose(data []byte, env map[string]string) []byte {
+	const escaped = "\x00"
+	text := strings.ReplaceAll(string(data), "$$", escaped)
+	text = composeVarRe.ReplaceAllStringFunc(text, func(ref string) string {
+		match := composeVarRe.FindStringSubmatch(ref)
+		value, set := env[strings.ToUpper(match[1])]
+		switch match[2] {
+		case ":-":
+			if value == "" {
+				return match[3]
+			}
+		case "-":
+			if !set {
+				return match[3]
+			}
+		}
+		return value
+	})
+	return []byte(strings.ReplaceAll(text, escaped, "$$"))
+}
+
+// shortSyntaxHostPort returns the host port of "[ip:]host:container[/proto]",
+// or "" when the port is not published on a fixed host port.
+func shortSyntaxHostPort(value string) string {
+	value, _, _ = strings.Cut(value, "/")
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// compareVersions compares dotted numeric versions, ignoring a leading "v" or
+// "go" and anything after the numbers. Missing parts count as zero.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	var parts []int
+	for _, part := range strings.Split(versionRe.FindString(version), ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,215 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func fakeExec(outputs map[string]string) func(string, ...string) (string, error) {
+	return func(name string, args ...string) (string, error) {
+		out, ok := outputs[strings.Join(append([]string{name}, args...), " ")]
+		if !ok {
+			return "", errors.New("executable file not found in $PATH")
+		}
+		return out, nil
+	}
+}
+
+func statuses(report Report) map[string]Status {
+	got := map[string]Status{}
+	for _, check := range report.Checks {
+		got[check.Group+"/"+check.Name] = check.Status
+	}
+	return got
+}
+
+var projectFiles = fstest.MapFS{
+	"package.json":    {Data: []byte(`{"packageManager": "bun@1.2.13", "engines": {"node": ">=22"}}`)},
+	"apps/api/go.mod": {Data: []byte("module example.com/demo\n\ngo 1.24.5\n")},
+	"docker-compose.yml": {Data: []byte(`services:
+  db:
+    ports:
+      - "5432:5432"
+  api:
+    ports:
+      - "127.0.0.1:8080:8080/tcp"
+      - target: 9000
+        published: "9000"
+      - "9100"
+`)},
+}
+
+func TestRunToolchainDockerAndPorts(t *testing.T) {
+	report := Run(Options{
+		Files: projectFiles,
+		Exec: fakeExec(map[string]string{
+			"go env GOVERSION": "go1.23.4",
+			"bun --version":    "1.2.15",
+			"node --version":   "v22.3.0",
+			"docker --version": "Docker version 27.1.1, build 6312585",
+		}),
+		PortFree: func(port int) bool { return port != 8080 },
+	})
+
+	want := map[string]Status{
+		"Toolchain/go":   StatusFail,
+		"Toolchain/bun":  StatusPass,
+		"Toolchain/node": StatusPass,
+		"Docker/docker":  StatusPass,
+		"Docker/daemon":  StatusWarn,
+		"Docker/compose": StatusWarn,
+		"Ports/5432":     StatusPass,
+		"Ports/8080":     StatusWarn,
+		"Ports/9000":     StatusPass,
+	}
+	if got := statuses(report); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected checks:\n got %v\nwant %v", got, want)
+	}
+	for _, check := range report.Checks {
+		if check.Status != StatusPass && check.Fix == "" {
+			t.Fatalf("expected a fix for %s/%s", check.Group, check.Name)
+		}
+	}
+}
+
+func TestRunInsideProjectFailsOnDockerAndChecksEnv(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("docker-compose.yml", "services: {}\n")
+	write("apps/web/.env.example", "VITE_API_URL=\"http://localhost:8080\"\n")
+	write("apps/api/.env.example", "API_PRIMARY.ENV=\"development\"\nAPI_SERVER.PORT=\"8080\"\nAPI_SERVER.READ_TIMEOUT=\"30s\"\nAPI_SMTP.FROM_EMAIL=\"a@b.c\"\n")
+	write("apps/api/.env", strings.Join([]string{
+		`API_PRIMARY.ENV="dev"   # typo`,
+		`API_SERVER.READ_TIMEOUT=30`,
+		`API_SMTP.FROM_EMAIL=not-an-email`,
+		`API_UNKNOWN="x"`,
+	}, "\n"))
+	write(APIConfigDir+"/config.go", "package config\n\nimport \"time\"\n\n"+`type Env string
+
+type Config struct {
+	Primary       Primary              `+"`koanf:\"primary\" validate:\"required\"`"+`
+	Server        ServerConfig         `+"`koanf:\"server\" validate:\"required\"`"+`
+	SMTP          SMTPConfig           `+"`koanf:\"smtp\" validate:\"required\"`"+`
+	Observability *ObservabilityConfig `+"`koanf:\"observability\"`"+`
+}
+
+type Primary struct {
+	Env Env `+"`koanf:\"env\" validate:\"required,oneof=development staging production\"`"+`
+}
+
+type ServerConfig struct {
+	Port        string        `+"`koanf:\"port\" validate:\"required\"`"+`
+	ReadTimeout time.Duration `+"`koanf:\"read_timeout\" validate:\"required\"`"+`
+}
+
+type SMTPConfig struct {
+	FromEmail string `+"`koanf:\"from_email\" validate:\"required,email\"`"+`
+}
+
+type ObservabilityConfig struct {
+	ServiceName string `+"`koanf:\"service_name\" validate:\"required\"`"+`
+}
+`)
+
+	report := Run(Options{
+		Root:     root,
+		Files:    os.DirFS(root),
+		Exec:     fakeExec(nil),
+		PortFree: func(int) bool { return true },
+	})
+
+	want := map[string]Status{
+		"Docker/docker":                       StatusFail,
+		"Environment/apps/api/.env":           StatusFail,
+		"Environment/apps/web/.env":           StatusFail,
+		"Environment/API_PRIMARY.ENV":         StatusFail,
+		"Environment/API_SERVER.PORT":         StatusFail,
+		"Environment/API_SERVER.READ_TIMEOUT": StatusFail,
+		"Environment/API_SMTP.FROM_EMAIL":     StatusFail,
+	}
+	if got := statuses(report); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected checks:\n got %v\nwant %v", got, want)
+	}
+	for _, check := range report.Checks {
+		switch check.Name {
+		case "apps/api/.env":
+			if check.Detail != "missing API_SERVER.PORT" {
+				t.Fatalf("unexpected key comparison: %s", check.Detail)
+			}
+		case "apps/web/.env":
+			if !strings.Contains(check.Fix, "cp apps/web/.env.example apps/web/.env") {
+				t.Fatalf("expected a copy hint, got %q", check.Fix)
+			}
+		case "API_PRIMARY.ENV":
+			if !strings.Contains(check.Detail, `got "dev"`) {
+				t.Fatalf("expected the inline comment to be stripped, got %q", check.Detail)
+			}
+		}
+	}
+}
+
+func TestComposePortsReadsEnv(t *testing.T) {
+	files := fstest.MapFS{
+		".env.example": {Data: []byte("API_PORT=\"9090\"\nWEB_PORT=\"\"\n")},
+		"docker-compose.yml": {Data: []byte(`services:
+  api:
+    ports:
+      - "${API_PORT:-8080}:${API_PORT:-8080}"
+  web:
+    ports:
+      - "${WEB_PORT:-3000}:3000"
+  db:
+    environment:
+      PASSWORD: "${DB_PASSWORD:?set DB_PASSWORD in .env}"
+    healthcheck:
+      test: ["CMD-SHELL", "echo $$PASSWORD"]
+    ports:
+      - "${DB_PORT}:5432"
+`)},
+	}
+	data, _ := files.ReadFile("docker-compose.yml")
+	compose := interpolateCompose(data, composeEnv(files))
+	if !strings.Contains(string(compose), "echo $$PASSWORD") {
+		t.Fatalf("expected escaped dollars to be kept:\n%s", compose)
+	}
+	ports, err := composePorts(compose)
+	if err != nil {
+		t.Fatalf("compose ports: %v", err)
+	}
+	want := []composePort{{port: 3000, service: "web"}, {port: 9090, service: "api"}}
+	if !reflect.DeepEqual(ports, want) {
+		t.Fatalf("unexpected ports: got %v, want %v", ports, want)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.24.5", "1.24.5", 0},
+		{"go1.25", "1.24.5", 1},
+		{"v20.19.5", "22", -1},
+		{"1.2.13", "1.2", 1},
+		{"1.22rc1", "1.22.0", 0},
+	}
+	for _, tc := range cases {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Fatalf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+)
+
+// APIConfigDir holds the API's config.Config, relative to the project root.
+const APIConfigDir = "apps/api/internal/infrastructure/config"
+
+const apiEnvFile = "apps/api/.env"
+
+// checkEnvironment compares every .env with its .env.example and validates the
+// API .env against the rules of config.Config.
+func checkEnvironment(root string) []Check {
+	examples, err := findEnvExamples(root)
+	if err != nil {
+		return []Check{{Group: GroupEnvironment, Name: ".env", Status: StatusFail, Detail: err.Error(), Fix: "check the permissions of the project directory"}}
+	}
+	var checks []Check
+	for _, example := range examples {
+		checks = append(checks, checkEnvFile(root, example))
+	}
+	return append(checks, checkAPIConfig(root)...)
+}
+
+func findEnvExamples(root string) ([]string, error) {
+	var examples []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "node_modules" || d.Name() == ".git") {
+			return fs.SkipDir
+		}
+		if !d.IsDir() && d.Name() == ".env.example" {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			examples = append(examples, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(examples)
+	return examples, err
+}
+
+func checkEnvFile(root, example string) Check {
+	envPath := strings.TrimSuffix(example, ".example")
+	check := Check{Group: GroupEnvironment, Name: envPath}
+
+	exampleData, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(example)))
+	if err != nil {
+		check.Status, check.Detail, check.Fix = StatusFail, err.Error(), "check that "+example+" is readable"
+		return check
+	}
+	envData, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(envPath)))
+	if errors.Is(err, fs.ErrNotExist) {
+		check.Status, check.Detail = StatusFail, "missing"
+		check.Fix = fmt.Sprintf("cp %s %s, then fill in real values", example, envPath)
+		return check
+	}
+	if err != nil {
+		check.Status, check.Detail, check.Fix = StatusFail, err.Error(), "check that "+envPath+" is readable"
+		return check
+	}
+
+	expected, _ := scaffold.ParseEnv(exampleData)
+	actual, _ := scaffold.ParseEnv(envData)
+	missing := missingKeys(expected, actual)
+	extra := missingKeys(actual, expected)
+	switch {
+	case len(missing) > 0:
+		check.Status = StatusFail
+		check.Detail = "missing " + strings.Join(missing, ", ")
+		check.Fix = fmt.Sprintf("copy the missing keys from %s into %s", example, envPath)
+	case len(extra) > 0:
+		check.Status = StatusWarn
+		check.Detail = "not in .env.example: " + strings.Join(extra, ", ")
+		check.Fix = fmt.Sprintf("remove the keys or document them in %s", example)
+	default:
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("all %d keys from .env.example are set", len(expected))
+	}
+	return check
+}
+
+func missingKeys(want, have []string) []string {
+	present := map[string]bool{}
+	for _, key := range have {
+		present[key] = true
+	}
+	var missing []string
+	for _, key := range want {
+		if !present[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// checkAPIConfig validates the API .env the way config.LoadConfig does.
+func checkAPIConfig(root string) []Check {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(apiEnvFile)))
+	if err != nil {
+		// A missing .env is already reported by checkEnvFile.
+		return nil
+	}
+	rules, err := loadConfigRules(filepath.Join(root, filepath.FromSlash(APIConfigDir)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []Check{{
+			Group:  GroupEnvironment,
+			Name:   "config.Config",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("cannot read the API config rules: %v", err),
+			Fix:    "make sure " + APIConfigDir + " compiles",
+		}}
+	}
+
+	_, values := scaffold.ParseEnv(data)
+	var checks []Check
+	for _, rule := range rules {
+		if problem := rule.check(values); problem != "" {
+			checks = append(checks, Check{
+				Group:  GroupEnvironment,
+				Name:   rule.key,
+				Status: StatusFail,
+				Detail: problem,
+				Fix:    fmt.Sprintf("set %s in %s", rule.key, apiEnvFile),
+			})
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, Check{
+			Group:  GroupEnvironment,
+			Name:   "config.Config",
+			Status: StatusPass,
+			Detail: fmt.Sprintf("%s satisfies %d config rules", apiEnvFile, len(rules)),
+		})
+	}
+	return checks
+}
@@ -0,0 +1,295 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldText   FieldType = "text"
+	FieldInt    FieldType = "int"
+	FieldInt64  FieldType = "int64"
+	FieldFloat  FieldType = "float"
+	FieldBool   FieldType = "bool"
+	FieldTime   FieldType = "time"
+	FieldUUID   FieldType = "uuid"
+)
+
+var fieldTypeAliases = map[string]FieldType{
+	"string":    FieldString,
+	"text":      FieldText,
+	"int":       FieldInt,
+	"integer":   FieldInt,
+	"int64":     FieldInt64,
+	"bigint":    FieldInt64,
+	"float":     FieldFloat,
+	"float64":   FieldFloat,
+	"bool":      FieldBool,
+	"boolean":   FieldBool,
+	"time":      FieldTime,
+	"timestamp": FieldTime,
+	"datetime":  FieldTime,
+	"uuid":      FieldUUID,
+}
+
+var reservedFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// fieldNameRe matches field names. Generated Go code only uses the Pascal form
+// of a field, so names that are Go keywords, such as type, are allowed.
+var fieldNameRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+type Field struct {
+	Names
+	Type     FieldType
+	Nullable bool
+	Unique   bool
+	Indexed  bool
+}
+
+// ParseField parses a field spec in the form name:type[:nullable][:unique][:indexed].
+func ParseField(spec string) (Field, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) < 2 {
+		return Field{}, fmt.Errorf("invalid field %q: expected name:type", spec)
+	}
+	if !fieldNameRe.MatchString(parts[0]) {
+		return Field{}, fmt.Errorf("invalid field %q: name must start with a letter and contain only letters, digits or underscores", spec)
+	}
+
+	field := Field{Names: NewNames(parts[0])}
+	if reservedFields[field.Snake] {
+		return Field{}, fmt.Errorf("invalid field %q: %s is managed by the base model", spec, field.Snake)
+	}
+
+	fieldType, ok := fieldTypeAliases[strings.ToLower(parts[1])]
+	if !ok {
+		return Field{}, fmt.Errorf("invalid field %q: unsupported type %q", spec, parts[1])
+	}
+	field.Type = fieldType
+
+	for _, modifier := range parts[2:] {
+		switch strings.ToLower(modifier) {
+		case "nullable", "null", "optional":
+			field.Nullable = true
+		case "unique":
+			field.Unique = true
+		case "indexed", "index":
+			field.Indexed = true
+		default:
+			return Field{}, fmt.Errorf("invalid field %q: unknown modifier %q", spec, modifier)
+		}
+	}
+	return field, nil
+}
+
+func ParseFields(specs []string) ([]Field, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("at least one field is required (name:type)")
+	}
+	seen := map[string]bool{}
+	fields := make([]Field, 0, len(specs))
+	for _, spec := range specs {
+		field, err := ParseField(spec)
+		if err != nil {
+			return nil, err
+		}
+		if seen[field.Snake] {
+			return nil, fmt.Errorf("duplicate field %q", field.Snake)
+		}
+		seen[field.Snake] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (f Field) baseGoType() string {
+	switch f.Type {
+	case FieldInt:
+		return "int"
+	case FieldInt64:
+		return "int64"
+	case FieldFloat:
+		return "float64"
+	case FieldBool:
+		return "bool"
+	case FieldTime:
+		return "time.Time"
+	case FieldUUID:
+		return "uuid.UUID"
+	default:
+		return "string"
+	}
+}
+
+func (f Field) GoType() string {
+	if f.Nullable {
+		return "*" + f.baseGoType()
+	}
+	return f.baseGoType()
+}
+
+func (f Field) PointerGoType() string {
+	return "*" + f.baseGoType()
+}
+
+func (f Field) GormTag() string {
+	var tags []string
+	if f.Unique {
+		tags = append(tags, "uniqueIndex")
+	} else if f.Indexed {
+		tags = append(tags, "index")
+	}
+	if !f.Nullable {
+		tags = append(tags, "not null")
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` gorm:"%s"`, strings.Join(tags, ";"))
+}
+
+func (f Field) JSONTag() string {
+	if f.Nullable {
+		return f.JSON + ",omitempty"
+	}
+	return f.JSON
+}
+
+// required reports whether the zero value of the field is meaningless, so the
+// store request can reject it. Numbers and booleans have valid zero values.
+func (f Field) required() bool {
+	if f.Nullable {
+		return false
+	}
+	switch f.Type {
+	case FieldString, FieldText, FieldTime, FieldUUID:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f Field) StoreValidateTag() string {
+	if f.required() {
+		return "required"
+	}
+	return "omitempty"
+}
+
+func (f Field) SQLType() string {
+	switch f.Type {
+	case FieldInt:
+		return "INTEGER"
+	case FieldInt64:
+		return "BIGINT"
+	case FieldFloat:
+		return "DOUBLE PRECISION"
+	case FieldBool:
+		return "BOOLEAN"
+	case FieldTime:
+		return "TIMESTAMPTZ"
+	case FieldUUID:
+		return "UUID"
+	default:
+		return "TEXT"
+	}
+}
+
+func (f Field) SQLColumn() string {
+	return sqlColumn(f.Snake, f.SQLType(), f.Nullable)
+}
+
+// MySQLType is the MySQL counterpart of SQLType. MySQL cannot index TEXT
+// without a prefix length, so unique or indexed text becomes VARCHAR(255).
+func (f Field) MySQLType() string {
+	switch f.Type {
+	case FieldInt:
+		return "INT"
+	case FieldInt64:
+		return "BIGINT"
+	case FieldFloat:
+		return "DOUBLE"
+	case FieldBool:
+		return "BOOLEAN"
+	case FieldTime:
+		return "DATETIME(6)"
+	case FieldUUID:
+		return "CHAR(36)"
+	case FieldText:
+		if f.Unique || f.Indexed {
+			return "VARCHAR(255)"
+		}
+		return "TEXT"
+	default:
+		return "VARCHAR(255)"
+	}
+}
+
+func (f Field) MySQLColumn() string {
+	return sqlColumn(f.Snake, f.MySQLType(), f.Nullable)
+}
+
+func sqlColumn(name, sqlType string, nullable bool) string {
+	column := fmt.Sprintf("%s %s", name, sqlType)
+	if !nullable {
+		column += " NOT NULL"
+	}
+	return column
+}
+
+func (f Field) ZodType() string {
+	var zod string
+	switch f.Type {
+	case FieldInt, FieldInt64:
+		zod = "z.number().int()"
+	case FieldFloat:
+		zod = "z.number()"
+	case FieldBool:
+		zod = "z.boolean()"
+	case FieldTime:
+		zod = "z.string().datetime()"
+	case FieldUUID:
+		zod = "z.string().uuid()"
+	default:
+		zod = "z.string()"
+	}
+	if f.Nullable {
+		zod += ".optional()"
+	}
+	return zod
+}
+
+// SampleJSON returns a literal usable as a request payload value in tests.
+func (f Field) SampleJSON() string {
+	switch f.Type {
+	case FieldInt, FieldInt64:
+		return "42"
+	case FieldFloat:
+		return "4.2"
+	case FieldBool:
+		return "true"
+	case FieldTime:
+		return `"2024-01-01T00:00:00Z"`
+	case FieldUUID:
+		return `"00000000-0000-0000-0000-000000000001"`
+	default:
+		return fmt.Sprintf("%q", "sample "+f.Words)
+	}
+}
+
+func (f Field) usesTime() bool {
+	return f.Type == FieldTime
+}
+
+func (f Field) usesUUID() bool {
+	return f.Type == FieldUUID
+}
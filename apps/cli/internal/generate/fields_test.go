@@ -0,0 +1,56 @@
+package generate
+
+import "testing"
+
+func TestParseField(t *testing.T) {
+	field, err := ParseField("owner_id:uuid:nullable:indexed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field.Pascal != "OwnerID" || field.JSON != "ownerId" || field.Snake != "owner_id" {
+		t.Fatalf("unexpected names: %+v", field.Names)
+	}
+	if field.Type != FieldUUID || !field.Nullable || !field.Indexed || field.Unique {
+		t.Fatalf("unexpected field: %+v", field)
+	}
+	if field.GoType() != "*uuid.UUID" {
+		t.Fatalf("expected pointer go type, got %s", field.GoType())
+	}
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	cases := []string{
+		"title",
+		"title:money",
+		"title:string:sparkly",
+		"1title:string",
+		"id:uuid",
+		"created_at:time",
+	}
+	for _, spec := range cases {
+		if _, err := ParseField(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestParseFieldsRejectsDuplicates(t *testing.T) {
+	if _, err := ParseFields([]string{"title:string", "Title:text"}); err == nil {
+		t.Fatalf("expected duplicate field error")
+	}
+}
+
+func TestNewNames(t *testing.T) {
+	cases := map[string]Names{
+		"BlogPost": {Pascal: "BlogPost", Camel: "blogPost", Snake: "blog_post", Kebab: "blog-post", Plural: "BlogPosts", SnakePlural: "blog_posts", KebabPlural: "blog-posts"},
+		"category": {Pascal: "Category", Camel: "category", Snake: "category", Kebab: "category", Plural: "Categories", SnakePlural: "categories", KebabPlural: "categories"},
+		"box":      {Pascal: "Box", Camel: "box", Snake: "box", Kebab: "box", Plural: "Boxes", SnakePlural: "boxes", KebabPlural: "boxes"},
+	}
+	for input, want := range cases {
+		got := NewNames(input)
+		if got.Pascal != want.Pascal || got.Camel != want.Camel || got.Snake != want.Snake || got.Kebab != want.Kebab ||
+			got.Plural != want.Plural || got.SnakePlural != want.SnakePlural || got.KebabPlural != want.KebabPlural {
+			t.Fatalf("NewNames(%q) = %+v, want %+v", input, got, want)
+		}
+	}
+}
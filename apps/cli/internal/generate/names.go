@@ -0,0 +1,126 @@
+package generate
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	wordBoundaryRe = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	wordSplitRe    = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"html": "HTML",
+	"http": "HTTP",
+	"id":   "ID",
+	"ip":   "IP",
+	"json": "JSON",
+	"sku":  "SKU",
+	"sql":  "SQL",
+	"uri":  "URI",
+	"url":  "URL",
+	"uuid": "UUID",
+}
+
+type Names struct {
+	Pascal      string
+	Camel       string
+	Snake       string
+	JSON        string
+	Kebab       string
+	Words       string
+	Plural      string
+	CamelPlural string
+	SnakePlural string
+	KebabPlural string
+}
+
+func NewNames(value string) Names {
+	words := splitWords(value)
+	plural := append([]string{}, words...)
+	if len(plural) > 0 {
+		plural[len(plural)-1] = pluralize(plural[len(plural)-1])
+	}
+	return Names{
+		Pascal:      pascal(words),
+		Camel:       camel(words),
+		Snake:       strings.Join(words, "_"),
+		JSON:        jsonCamel(words),
+		Kebab:       strings.Join(words, "-"),
+		Words:       strings.Join(words, " "),
+		Plural:      pascal(plural),
+		CamelPlural: camel(plural),
+		SnakePlural: strings.Join(plural, "_"),
+		KebabPlural: strings.Join(plural, "-"),
+	}
+}
+
+func splitWords(value string) []string {
+	value = wordBoundaryRe.ReplaceAllString(strings.TrimSpace(value), "$1 $2")
+	parts := wordSplitRe.Split(value, -1)
+	words := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		words = append(words, strings.ToLower(part))
+	}
+	return words
+}
+
+func pascal(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if initialism, ok := initialisms[word]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(upperFirst(word))
+	}
+	return b.String()
+}
+
+func camel(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0] + pascal(words[1:])
+}
+
+// jsonCamel matches the JSON naming of the template models (googleId, not
+// googleID), so initialisms are not upper-cased.
+func jsonCamel(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(words[0])
+	for _, word := range words[1:] {
+		b.WriteString(upperFirst(word))
+	}
+	return b.String()
+}
+
+func upperFirst(value string) string {
+	runes := []rune(value)
+	if len(runes) == 0 {
+		return value
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func pluralize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return strings.TrimSuffix(word, "y") + "ies"
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	default:
+		return word + "s"
+	}
+}
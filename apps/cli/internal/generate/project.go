@@ -0,0 +1,149 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	apiDir     = "apps/api"
+	zodPackage = "packages/zod"
+)
+
+const (
+	DatabasePostgres = "postgres"
+	DatabaseMySQL    = "mysql"
+)
+
+type Project struct {
+	Root       string
+	ModulePath string
+	ZodPackage string
+	// PackageManager is the tool named by the root package.json, e.g. "pnpm".
+	PackageManager string
+	// Database is the SQL dialect of the migrations, e.g. "mysql".
+	Database string
+}
+
+func (p Project) APIPath(parts ...string) string {
+	return filepath.Join(append([]string{p.Root, apiDir}, parts...)...)
+}
+
+func (p Project) Path(parts ...string) string {
+	return filepath.Join(append([]string{p.Root}, parts...)...)
+}
+
+// FindProject walks up from start until it finds a generated project root,
+// identified by an apps/api/go.mod file.
+func FindProject(start string) (Project, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return Project{}, err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, apiDir, "go.mod")); err == nil {
+			return LoadProject(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Project{}, errors.New("not inside a go-kickstart project (apps/api/go.mod not found)")
+		}
+		dir = parent
+	}
+}
+
+func LoadProject(root string) (Project, error) {
+	project := Project{Root: root}
+
+	modulePath, err := readModulePath(project.APIPath("go.mod"))
+	if err != nil {
+		return project, err
+	}
+	project.ModulePath = modulePath
+
+	project.Database, err = readDatabase(project.APIPath("go.mod"))
+	if err != nil {
+		return project, err
+	}
+
+	project.PackageManager, err = readPackageManager(project.Path("package.json"))
+	if err != nil {
+		return project, err
+	}
+
+	data, err := os.ReadFile(project.Path(zodPackage, "package.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return project, nil
+		}
+		return project, err
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return project, fmt.Errorf("parse %s/package.json: %w", zodPackage, err)
+	}
+	project.ZodPackage = pkg.Name
+	return project, nil
+}
+
+func readModulePath(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
+		}
+	}
+	return "", fmt.Errorf("module directive not found in %s", path)
+}
+
+// readDatabase tells the SQL dialect from the GORM driver the go.mod at path
+// requires directly.
+func readDatabase(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "require" {
+			fields = fields[1:]
+		}
+		if len(fields) >= 2 && fields[0] == "gorm.io/driver/mysql" && !strings.Contains(line, "// indirect") {
+			return DatabaseMySQL, nil
+		}
+	}
+	return DatabasePostgres, nil
+}
+
+// readPackageManager returns the name in the packageManager field of the
+// package.json at path. Projects without one predate the choice and use bun.
+func readPackageManager(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "bun", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", fmt.Errorf("parse package.json: %w", err)
+	}
+	name, _, _ := strings.Cut(pkg.PackageManager, "@")
+	if name == "" {
+		return "bun", nil
+	}
+	return name, nil
+}
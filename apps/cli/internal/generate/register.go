@@ -0,0 +1,244 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+)
+
+// goEdit describes an insertion into a Go source file. locate returns the
+// position text is inserted at; with lineStart the insertion moves to the
+// beginning of that line so closing braces keep their indentation.
+type goEdit struct {
+	text      string
+	lineStart bool
+	locate    func(fset *token.FileSet, file *ast.File) (token.Pos, error)
+}
+
+func editGoFile(path string, edits ...goEdit) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	type insertion struct {
+		offset int
+		text   string
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	insertions := make([]insertion, 0, len(edits))
+	for _, edit := range edits {
+		pos, err := edit.locate(fset, file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		offset := fset.Position(pos).Offset
+		if edit.lineStart {
+			offset = strings.LastIndex(string(src[:offset]), "\n") + 1
+		}
+		insertions = append(insertions, insertion{offset: offset, text: edit.text})
+	}
+	sort.SliceStable(insertions, func(i, j int) bool {
+		return insertions[i].offset > insertions[j].offset
+	})
+
+	out := string(src)
+	for _, ins := range insertions {
+		out = out[:ins.offset] + ins.text + out[ins.offset:]
+	}
+	formatted, err := format.Source([]byte(out))
+	if err != nil {
+		return fmt.Errorf("%s: format: %w", path, err)
+	}
+	return os.WriteFile(path, formatted, info.Mode())
+}
+
+// beforeStructClose inserts just before the closing brace of a struct type.
+func beforeStructClose(structName string) func(*token.FileSet, *ast.File) (token.Pos, error) {
+	return func(_ *token.FileSet, file *ast.File) (token.Pos, error) {
+		spec := findTypeSpec(file, structName)
+		if spec == nil {
+			return token.NoPos, fmt.Errorf("type %s not found", structName)
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return token.NoPos, fmt.Errorf("type %s is not a struct", structName)
+		}
+		return st.Fields.Closing, nil
+	}
+}
+
+// beforeTypeDecl inserts just before the declaration of the named type.
+func beforeTypeDecl(typeName string) func(*token.FileSet, *ast.File) (token.Pos, error) {
+	return func(_ *token.FileSet, file *ast.File) (token.Pos, error) {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+					if gen.Doc != nil {
+						return gen.Doc.Pos(), nil
+					}
+					return gen.Pos(), nil
+				}
+			}
+		}
+		return token.NoPos, fmt.Errorf("type %s not found", typeName)
+	}
+}
+
+// beforeLiteralClose inserts before the closing brace of the composite literal
+// of typeName inside funcName.
+func beforeLiteralClose(funcName, typeName string) func(*token.FileSet, *ast.File) (token.Pos, error) {
+	return func(_ *token.FileSet, file *ast.File) (token.Pos, error) {
+		fn := findFunc(file, funcName)
+		if fn == nil {
+			return token.NoPos, fmt.Errorf("func %s not found", funcName)
+		}
+		var found *ast.CompositeLit
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			if ident, ok := lit.Type.(*ast.Ident); ok && ident.Name == typeName {
+				found = lit
+			}
+			return found == nil
+		})
+		if found == nil {
+			return token.NoPos, fmt.Errorf("%s literal not found in %s", typeName, funcName)
+		}
+		return found.Rbrace, nil
+	}
+}
+
+// afterLastCall inserts after the last top-level call to callee in funcName,
+// falling back to the end of the function body.
+func afterLastCall(funcName, callee string) func(*token.FileSet, *ast.File) (token.Pos, error) {
+	return func(_ *token.FileSet, file *ast.File) (token.Pos, error) {
+		fn := findFunc(file, funcName)
+		if fn == nil {
+			return token.NoPos, fmt.Errorf("func %s not found", funcName)
+		}
+		pos := fn.Body.Rbrace
+		for _, stmt := range fn.Body.List {
+			expr, ok := stmt.(*ast.ExprStmt)
+			if !ok {
+				continue
+			}
+			call, ok := expr.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == callee {
+				pos = stmt.End()
+			}
+		}
+		return pos, nil
+	}
+}
+
+func findTypeSpec(file *ast.File, name string) *ast.TypeSpec {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == name {
+				return ts
+			}
+		}
+	}
+	return nil
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name && fn.Body != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// insertTSExport adds an `export * from` line to a barrel file and keeps the
+// export block sorted.
+func insertTSExport(path, module string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintf("export * from './%s.js'", module)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for _, existing := range lines {
+		if existing == line {
+			return nil
+		}
+	}
+	lines = append(lines, line)
+	var exports, rest []string
+	for _, l := range lines {
+		if strings.HasPrefix(l, "export * from ") {
+			exports = append(exports, l)
+			continue
+		}
+		rest = append(rest, l)
+	}
+	sort.Strings(exports)
+	return os.WriteFile(path, []byte(strings.Join(append(rest, exports...), "\n")+"\n"), 0o644)
+}
+
+// registerTSContract imports a contract into contracts/index.ts and adds it to
+// the apiContract router.
+func registerTSContract(path, key, module string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	importLine := fmt.Sprintf("import { %sContract } from './%s.js'", key, module)
+	if strings.Contains(content, importLine) {
+		return nil
+	}
+
+	lines := strings.Split(content, "\n")
+	lastImport := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "import ") {
+			lastImport = i
+		}
+	}
+	lines = append(lines[:lastImport+1], append([]string{importLine}, lines[lastImport+1:]...)...)
+	content = strings.Join(lines, "\n")
+
+	const routerOpen = "c.router({"
+	start := strings.Index(content, routerOpen)
+	if start < 0 {
+		return fmt.Errorf("%s: api contract router not found", path)
+	}
+	end := strings.Index(content[start:], "\n})")
+	if end < 0 {
+		return fmt.Errorf("%s: api contract router is not closed", path)
+	}
+	end += start
+	entry := fmt.Sprintf("\n\t%s: %sContract,", key, key)
+	content = content[:end] + entry + content[end:]
+	return os.WriteFile(path, []byte(content), 0o644)
+}
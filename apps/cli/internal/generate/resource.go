@@ -0,0 +1,327 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/template"
+)
+
+var migrationNumberRe = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+type Resource struct {
+	Names
+	Module     string
+	ZodPackage string
+	Fields     []Field
+}
+
+func (r Resource) UsesTime() bool {
+	for _, f := range r.Fields {
+		if f.usesTime() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Resource) UsesUUID() bool {
+	for _, f := range r.Fields {
+		if f.usesUUID() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Resource) HasUnique() bool {
+	for _, f := range r.Fields {
+		if f.Unique {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Resource) HasRequired() bool {
+	for _, f := range r.Fields {
+		if f.required() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Resource) ReversedFields() []Field {
+	out := make([]Field, len(r.Fields))
+	for i, f := range r.Fields {
+		out[len(r.Fields)-1-i] = f
+	}
+	return out
+}
+
+type generatedFile struct {
+	path   string
+	tmpl   *template.Template
+	goFile bool
+}
+
+type Result struct {
+	Created []string
+	Updated []string
+}
+
+// AddResource generates a full CRUD slice for name inside project and wires it
+// into the repository, service, handler, router and TypeScript contracts.
+func AddResource(project Project, name string, fields []Field) (Result, error) {
+	names := NewNames(name)
+	if names.Pascal == "" {
+		return Result{}, errors.New("resource name is required")
+	}
+	// The generated code only uses the name in its Pascal form or as the
+	// prefix of a longer identifier, so keywords such as package are fine but
+	// the name has to start with a letter.
+	if !token.IsIdentifier(names.Pascal) {
+		return Result{}, fmt.Errorf("invalid resource name %q: it must start with a letter", name)
+	}
+	if len(fields) == 0 {
+		return Result{}, errors.New("at least one field is required (name:type)")
+	}
+	res := Resource{
+		Names:      names,
+		Module:     project.ModulePath,
+		ZodPackage: project.ZodPackage,
+		Fields:     fields,
+	}
+
+	domainPath := project.APIPath("internal", "domain", names.Snake+".go")
+	if _, err := os.Stat(domainPath); err == nil {
+		return Result{}, fmt.Errorf("resource %s already exists (%s)", names.Pascal, project.rel(domainPath))
+	}
+
+	migrationNumber, err := nextMigrationNumber(project.APIPath("internal", "infrastructure", "database", "migrations"))
+	if err != nil {
+		return Result{}, err
+	}
+	migrationBase := fmt.Sprintf("%06d_create_%s", migrationNumber, names.SnakePlural)
+	migrationUp, migrationDown := migrationUpTmpl, migrationDownTmpl
+	if project.Database == DatabaseMySQL {
+		migrationUp, migrationDown = migrationUpMySQLTmpl, migrationDownMySQLTmpl
+	}
+
+	files := []generatedFile{
+		{domainPath, domainTmpl, true},
+		{project.APIPath("internal", "infrastructure", "repository", names.Snake+".go"), repositoryTmpl, true},
+		{project.APIPath("internal", "application", "dto", names.Snake+".go"), applicationDTOTmpl, true},
+		{project.APIPath("internal", "application", names.Snake+".go"), serviceTmpl, true},
+		{project.APIPath("internal", "interface", "http", "dto", names.Snake+".go"), httpDTOTmpl, true},
+		{project.APIPath("internal", "interface", "http", "handler", names.Snake+".go"), handlerTmpl, true},
+		{project.APIPath("internal", "interface", "http", "handler", names.Snake+"_test.go"), handlerTestTmpl, true},
+		{project.APIPath("internal", "infrastructure", "database", "migrations", migrationBase+".up.sql"), migrationUp, false},
+		{project.APIPath("internal", "infrastructure", "database", "migrations", migrationBase+".down.sql"), migrationDown, false},
+	}
+	if res.ZodPackage != "" {
+		files = append(files,
+			generatedFile{project.Path(zodPackage, "src", names.Kebab+".ts"), zodTmpl, false},
+			generatedFile{project.Path("packages", "openapi", "src", "contracts", names.Kebab+".ts"), contractTmpl, false},
+		)
+	}
+
+	// Render and check every file first so a bad name or an existing file
+	// leaves the project untouched.
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		if _, err := os.Stat(f.path); err == nil {
+			return Result{}, fmt.Errorf("refusing to overwrite existing file %s", project.rel(f.path))
+		}
+		content, err := render(f.tmpl, res, f.goFile)
+		if err != nil {
+			return Result{}, fmt.Errorf("render %s: %w", project.rel(f.path), err)
+		}
+		contents[i] = content
+	}
+
+	changes := &changeSet{original: map[string][]byte{}}
+	var result Result
+	for i, f := range files {
+		if err := changes.create(f.path, contents[i]); err != nil {
+			changes.undo()
+			return Result{}, err
+		}
+		result.Created = append(result.Created, project.rel(f.path))
+	}
+	updated, err := registerResource(project, res, changes)
+	if err != nil {
+		changes.undo()
+		return Result{}, err
+	}
+	result.Updated = updated
+	return result, nil
+}
+
+// changeSet records the files AddResource creates and the content of the
+// files it edits, so a failure part way can put the project back.
+type changeSet struct {
+	created  []string
+	original map[string][]byte
+}
+
+func (c *changeSet) create(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return err
+	}
+	c.created = append(c.created, path)
+	return nil
+}
+
+// save keeps the content of path before its first edit.
+func (c *changeSet) save(path string) error {
+	if _, ok := c.original[path]; ok {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	c.original[path] = data
+	return nil
+}
+
+func (c *changeSet) undo() {
+	for _, path := range c.created {
+		_ = os.Remove(path)
+	}
+	for path, data := range c.original {
+		_ = os.WriteFile(path, data, 0o644)
+	}
+}
+
+func registerResource(project Project, res Resource, changes *changeSet) ([]string, error) {
+	var updated []string
+	portDecl, err := render(portTmpl, res, false)
+	if err != nil {
+		return updated, err
+	}
+
+	goEdits := []struct {
+		path  string
+		edits []goEdit
+	}{
+		{
+			project.APIPath("internal", "application", "port", "repositories.go"),
+			[]goEdit{
+				{text: string(portDecl), locate: beforeTypeDecl("Repositories")},
+				{text: fmt.Sprintf("\t%s %sRepository\n", res.Pascal, res.Pascal), lineStart: true, locate: beforeStructClose("Repositories")},
+			},
+		},
+		{
+			project.APIPath("internal", "infrastructure", "repository", "repositories.go"),
+			[]goEdit{
+				{text: fmt.Sprintf("\t\t%s: New%sRepository(s.Config, s.DB.DB, cacheClient),\n", res.Pascal, res.Pascal), lineStart: true, locate: beforeLiteralClose("NewRepositories", "Repositories")},
+			},
+		},
+		{
+			project.APIPath("internal", "application", "services.go"),
+			[]goEdit{
+				{text: fmt.Sprintf("\t%s %sService\n", res.Pascal, res.Pascal), lineStart: true, locate: beforeStructClose("Services")},
+				{text: fmt.Sprintf("\t\t%s: New%sService(repos.%s),\n", res.Pascal, res.Pascal, res.Pascal), lineStart: true, locate: beforeLiteralClose("NewServices", "Services")},
+			},
+		},
+		{
+			project.APIPath("internal", "interface", "http", "handler", "handlers.go"),
+			[]goEdit{
+				{text: fmt.Sprintf("\t%s *%sHandler\n", res.Pascal, res.Pascal), lineStart: true, locate: beforeStructClose("Handlers")},
+				{text: fmt.Sprintf("\t\t%s: New%sHandler(h, services.%s),\n", res.Pascal, res.Pascal, res.Pascal), lineStart: true, locate: beforeLiteralClose("NewHandlers", "Handlers")},
+			},
+		},
+		{
+			project.APIPath("internal", "interface", "http", "router", "routes.go"),
+			[]goEdit{
+				{text: fmt.Sprintf("\n\tresource(protected, \"/%s\", h.%s.ResourceHandler)", res.KebabPlural, res.Pascal), locate: afterLastCall("registerRoutes", "resource")},
+			},
+		},
+	}
+	for _, e := range goEdits {
+		if err := changes.save(e.path); err != nil {
+			return updated, err
+		}
+		if err := editGoFile(e.path, e.edits...); err != nil {
+			return updated, err
+		}
+		updated = append(updated, project.rel(e.path))
+	}
+
+	if res.ZodPackage == "" {
+		return updated, nil
+	}
+	zodIndex := project.Path(zodPackage, "src", "index.ts")
+	if err := changes.save(zodIndex); err != nil {
+		return updated, err
+	}
+	if err := insertTSExport(zodIndex, res.Kebab); err != nil {
+		return updated, err
+	}
+	updated = append(updated, project.rel(zodIndex))
+
+	contractsIndex := project.Path("packages", "openapi", "src", "contracts", "index.ts")
+	if err := changes.save(contractsIndex); err != nil {
+		return updated, err
+	}
+	if err := registerTSContract(contractsIndex, res.JSON, res.Kebab); err != nil {
+		return updated, err
+	}
+	updated = append(updated, project.rel(contractsIndex))
+	return updated, nil
+}
+
+func render(tmpl *template.Template, data any, goFile bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if !goFile {
+		return buf.Bytes(), nil
+	}
+	return format.Source(buf.Bytes())
+}
+
+func nextMigrationNumber(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	var numbers []int
+	for _, entry := range entries {
+		match := migrationNumberRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	if len(numbers) == 0 {
+		return 1, nil
+	}
+	sort.Ints(numbers)
+	return numbers[len(numbers)-1] + 1, nil
+}
+
+func (p Project) rel(path string) string {
+	if rel, err := filepath.Rel(p.Root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,222 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+)
+
+func TestAddResource_EmbeddedTemplate(t *testing.T) {
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	if err := scaffold.ScaffoldProject(cfg, scaffold.Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	project, err := FindProject(filepath.Join(cfg.Destination, "apps", "api", "internal"))
+	if err != nil {
+		t.Fatalf("find project: %v", err)
+	}
+	if project.ModulePath != "github.com/acme/demo" {
+		t.Fatalf("unexpected module path %q", project.ModulePath)
+	}
+	if project.ZodPackage != "@demo/zod" {
+		t.Fatalf("unexpected zod package %q", project.ZodPackage)
+	}
+	if project.PackageManager != "bun" {
+		t.Fatalf("unexpected package manager %q", project.PackageManager)
+	}
+	if project.Database != DatabasePostgres {
+		t.Fatalf("unexpected database %q", project.Database)
+	}
+
+	fields, err := ParseFields([]string{"title:string", "sku:string:unique", "price:float", "published_at:time:nullable"})
+	if err != nil {
+		t.Fatalf("parse fields: %v", err)
+	}
+	result, err := AddResource(project, "BlogPost", fields)
+	if err != nil {
+		t.Fatalf("add resource: %v", err)
+	}
+	if len(result.Created) == 0 || len(result.Updated) == 0 {
+		t.Fatalf("expected created and updated files, got %+v", result)
+	}
+	// Go keywords are valid resource and field names.
+	keywordFields, err := ParseFields([]string{"type:string", "range:int"})
+	if err != nil {
+		t.Fatalf("parse fields: %v", err)
+	}
+	if _, err := AddResource(project, "Package", keywordFields); err != nil {
+		t.Fatalf("add keyword resource: %v", err)
+	}
+
+	api := filepath.Join(cfg.Destination, "apps", "api")
+	assertGoTypeChecks(t, api)
+
+	assertContains(t, filepath.Join(api, "internal/interface/http/router/routes.go"), `resource(protected, "/blog-posts", h.BlogPost.ResourceHandler)`)
+	assertContains(t, filepath.Join(api, "internal/application/port/repositories.go"), "BlogPost          BlogPostRepository")
+	assertContains(t, filepath.Join(api, "internal/infrastructure/repository/repositories.go"), "NewBlogPostRepository(s.Config, s.DB.DB, cacheClient)")
+	assertContains(t, filepath.Join(api, "internal/application/services.go"), "NewBlogPostService(repos.BlogPost)")
+	assertContains(t, filepath.Join(api, "internal/interface/http/handler/handlers.go"), "NewBlogPostHandler(h, services.BlogPost)")
+	assertContains(t, filepath.Join(api, "internal/infrastructure/database/migrations/000002_create_blog_posts.up.sql"), "idx_blog_posts_sku_active")
+	assertContains(t, filepath.Join(api, "internal/domain/blog_post.go"), `json:"publishedAt,omitempty"`)
+	assertContains(t, filepath.Join(cfg.Destination, "packages/zod/src/index.ts"), "export * from './blog-post.js'")
+	assertContains(t, filepath.Join(cfg.Destination, "packages/openapi/src/contracts/index.ts"), "blogPost: blogPostContract,")
+
+	if _, err := AddResource(project, "BlogPost", fields); err == nil {
+		t.Fatalf("expected adding an existing resource to fail")
+	}
+}
+
+func TestAddResource_LeavesProjectUntouchedOnError(t *testing.T) {
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	if err := scaffold.ScaffoldProject(cfg, scaffold.Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+	project, err := LoadProject(cfg.Destination)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	fields, err := ParseFields([]string{"title:string"})
+	if err != nil {
+		t.Fatalf("parse fields: %v", err)
+	}
+	domain := filepath.Join(cfg.Destination, "apps/api/internal/domain/article.go")
+
+	// An existing target is found before anything is written.
+	contract := filepath.Join(cfg.Destination, "packages/openapi/src/contracts/article.ts")
+	if err := os.WriteFile(contract, []byte("// mine\n"), 0o644); err != nil {
+		t.Fatalf("write contract: %v", err)
+	}
+	if _, err := AddResource(project, "Article", fields); err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Fatalf("expected an overwrite error, got %v", err)
+	}
+	if _, err := os.Stat(domain); !os.IsNotExist(err) {
+		t.Fatalf("expected no files to be written, stat domain: %v", err)
+	}
+	if err := os.Remove(contract); err != nil {
+		t.Fatalf("remove contract: %v", err)
+	}
+
+	// A failed registration removes the new files and restores the edits.
+	services := filepath.Join(cfg.Destination, "apps/api/internal/application/services.go")
+	before, err := os.ReadFile(services)
+	if err != nil {
+		t.Fatalf("read services: %v", err)
+	}
+	routes := filepath.Join(cfg.Destination, "apps/api/internal/interface/http/router/routes.go")
+	if err := os.WriteFile(routes, []byte("package router\n"), 0o644); err != nil {
+		t.Fatalf("write routes: %v", err)
+	}
+	if _, err := AddResource(project, "Article", fields); err == nil {
+		t.Fatalf("expected the route registration to fail")
+	}
+	if _, err := os.Stat(domain); !os.IsNotExist(err) {
+		t.Fatalf("expected the generated files to be removed, stat domain: %v", err)
+	}
+	after, err := os.ReadFile(services)
+	if err != nil {
+		t.Fatalf("read services: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("expected services.go to be restored, got:\n%s", after)
+	}
+}
+
+func TestAddResource_MySQLMigrations(t *testing.T) {
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	scaffold.SetDatabaseType(&cfg, scaffold.DatabaseMySQL)
+	if err := scaffold.ScaffoldProject(cfg, scaffold.Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	project, err := LoadProject(cfg.Destination)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	if project.Database != DatabaseMySQL {
+		t.Fatalf("unexpected database %q", project.Database)
+	}
+
+	fields, err := ParseFields([]string{"sku:string:unique", "body:text", "owner_id:uuid:indexed"})
+	if err != nil {
+		t.Fatalf("parse fields: %v", err)
+	}
+	if _, err := AddResource(project, "Product", fields); err != nil {
+		t.Fatalf("add resource: %v", err)
+	}
+
+	migrations := filepath.Join(cfg.Destination, "apps/api/internal/infrastructure/database/migrations")
+	up := filepath.Join(migrations, "000002_create_products.up.sql")
+	assertContains(t, up, "sku VARCHAR(255) NOT NULL,")
+	assertContains(t, up, "body TEXT NOT NULL,")
+	assertContains(t, up, "owner_id CHAR(36) NOT NULL,")
+	assertContains(t, up, "UNIQUE INDEX idx_products_sku_active (sku, active),")
+	assertContains(t, up, "INDEX idx_products_owner_id (owner_id),")
+	assertContains(t, filepath.Join(migrations, "000002_create_products.down.sql"), "DROP TABLE IF EXISTS products;")
+
+	data, err := os.ReadFile(up)
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if strings.Contains(string(data), "TIMESTAMPTZ") || strings.Contains(string(data), "WHERE") {
+		t.Fatalf("expected a MySQL migration, got:\n%s", data)
+	}
+}
+
+// assertGoTypeChecks type-checks the Go module at root like the template lint.
+func assertGoTypeChecks(t *testing.T, root string) {
+	t.Helper()
+
+	files := map[string][]byte{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || (!strings.HasSuffix(path, ".go") && info.Name() != "go.mod") {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)], err = os.ReadFile(path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("read generated go files: %v", err)
+	}
+	findings, err := scaffold.CheckGo(files)
+	if err != nil {
+		t.Fatalf("type-check generated go files: %v", err)
+	}
+	for _, f := range findings {
+		t.Errorf("generated code: %s", f)
+	}
+	if len(findings) > 0 {
+		t.FailNow()
+	}
+}
+
+func assertContains(t *testing.T, path string, needle string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if !strings.Contains(string(data), needle) {
+		t.Fatalf("expected %s to contain %q", path, needle)
+	}
+}
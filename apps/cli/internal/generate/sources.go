@@ -0,0 +1,380 @@
+package generate
+
+import "text/template"
+
+var domainTmpl = template.Must(template.New("domain").Parse(`package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type {{.Pascal}} struct {
+	ID        uuid.UUID      ` + "`" + `json:"id" gorm:"type:uuid;primaryKey"` + "`" + `
+	CreatedAt time.Time      ` + "`" + `json:"createdAt"` + "`" + `
+	UpdatedAt time.Time      ` + "`" + `json:"updatedAt"` + "`" + `
+	DeletedAt gorm.DeletedAt ` + "`" + `json:"deletedAt"` + "`" + `
+
+{{range .Fields}}	{{.Pascal}} {{.GoType}} ` + "`" + `json:"{{.JSONTag}}"{{.GormTag}}` + "`" + `
+{{end}}}
+
+func (m {{.Pascal}}) GetID() uuid.UUID {
+	return m.ID
+}
+`))
+
+var repositoryTmpl = template.Must(template.New("repository").Parse(`package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"{{.Module}}/internal/application/port"
+	"{{.Module}}/internal/domain"
+	"{{.Module}}/internal/infrastructure/config"
+	"{{.Module}}/internal/infrastructure/lib/cache"
+	"gorm.io/gorm"
+)
+
+type {{.Pascal}}Repository = port.{{.Pascal}}Repository
+
+type {{.Camel}}Repository struct {
+	ResourceRepository[domain.{{.Pascal}}]
+}
+
+func New{{.Pascal}}Repository(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) {{.Pascal}}Repository {
+	return &{{.Camel}}Repository{
+		ResourceRepository: NewResourceRepository[domain.{{.Pascal}}](cfg, db, cacheClient),
+	}
+}
+
+func (r *{{.Camel}}Repository) Store(ctx context.Context, entity *domain.{{.Pascal}}) error {
+	if entity.ID == uuid.Nil {
+		entity.ID = uuid.New()
+	}
+	return r.ResourceRepository.Store(ctx, entity)
+}
+`))
+
+var portTmpl = template.Must(template.New("port").Parse(`type {{.Pascal}}Repository interface {
+	ResourceRepository[domain.{{.Pascal}}]
+}
+
+`))
+
+var applicationDTOTmpl = template.Must(template.New("applicationDTO").Parse(`package dto
+
+import (
+{{- if .UsesTime}}
+	"time"
+{{end}}
+{{if .UsesUUID}}	"github.com/google/uuid"
+{{end}}	"{{.Module}}/internal/domain"
+)
+
+type Store{{.Pascal}}Input struct {
+{{range .Fields}}	{{.Pascal}} {{.GoType}}
+{{end}}}
+
+func (d *Store{{.Pascal}}Input) ToModel() *domain.{{.Pascal}} {
+	return &domain.{{.Pascal}}{
+{{range .Fields}}		{{.Pascal}}: d.{{.Pascal}},
+{{end}}	}
+}
+
+type Update{{.Pascal}}Input struct {
+{{range .Fields}}	{{.Pascal}} {{.PointerGoType}}
+{{end}}}
+
+func (d *Update{{.Pascal}}Input) ToModel() *domain.{{.Pascal}} {
+	entity := &domain.{{.Pascal}}{}
+{{range .Fields}}	if d.{{.Pascal}} != nil {
+		entity.{{.Pascal}} = {{if not .Nullable}}*{{end}}d.{{.Pascal}}
+	}
+{{end}}	return entity
+}
+
+func (d *Update{{.Pascal}}Input) ToMap() map[string]any {
+	updates := make(map[string]any)
+{{range .Fields}}	if d.{{.Pascal}} != nil {
+		updates["{{.Snake}}"] = *d.{{.Pascal}}
+	}
+{{end}}	return updates
+}
+`))
+
+var serviceTmpl = template.Must(template.New("service").Parse(`package application
+
+import (
+	applicationdto "{{.Module}}/internal/application/dto"
+	"{{.Module}}/internal/application/port"
+	"{{.Module}}/internal/domain"
+)
+
+type {{.Pascal}}Service interface {
+	ResourceService[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input]
+}
+
+type {{.Camel}}Service struct {
+	ResourceService[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input]
+	repo port.{{.Pascal}}Repository
+}
+
+func New{{.Pascal}}Service(repo port.{{.Pascal}}Repository) {{.Pascal}}Service {
+	return &{{.Camel}}Service{
+		ResourceService: NewResourceService[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input]("{{.Words}}", repo),
+		repo:            repo,
+	}
+}
+`))
+
+var httpDTOTmpl = template.Must(template.New("httpDTO").Parse(`package dto
+
+import (
+{{- if .UsesTime}}
+	"time"
+{{end}}
+	"github.com/go-playground/validator/v10"
+{{- if .UsesUUID}}
+	"github.com/google/uuid"
+{{- end}}
+	applicationdto "{{.Module}}/internal/application/dto"
+)
+
+type Store{{.Pascal}}Request struct {
+{{range .Fields}}	{{.Pascal}} {{.GoType}} ` + "`" + `json:"{{.JSON}}" validate:"{{.StoreValidateTag}}"` + "`" + `
+{{end}}}
+
+func (d *Store{{.Pascal}}Request) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *Store{{.Pascal}}Request) ToUsecase() *applicationdto.Store{{.Pascal}}Input {
+	return &applicationdto.Store{{.Pascal}}Input{
+{{range .Fields}}		{{.Pascal}}: d.{{.Pascal}},
+{{end}}	}
+}
+
+type Update{{.Pascal}}Request struct {
+{{range .Fields}}	{{.Pascal}} {{.PointerGoType}} ` + "`" + `json:"{{.JSON}}" validate:"omitempty"` + "`" + `
+{{end}}}
+
+func (d *Update{{.Pascal}}Request) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *Update{{.Pascal}}Request) ToUsecase() *applicationdto.Update{{.Pascal}}Input {
+	return &applicationdto.Update{{.Pascal}}Input{
+{{range .Fields}}		{{.Pascal}}: d.{{.Pascal}},
+{{end}}	}
+}
+`))
+
+var handlerTmpl = template.Must(template.New("handler").Parse(`package handler
+
+import (
+	"{{.Module}}/internal/application"
+	applicationdto "{{.Module}}/internal/application/dto"
+	"{{.Module}}/internal/domain"
+	httpdto "{{.Module}}/internal/interface/http/dto"
+)
+
+type {{.Pascal}}Handler struct {
+	*ResourceHandler[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input, *httpdto.Store{{.Pascal}}Request, *httpdto.Update{{.Pascal}}Request]
+}
+
+func New{{.Pascal}}Handler(h Handler, service application.{{.Pascal}}Service) *{{.Pascal}}Handler {
+	return &{{.Pascal}}Handler{
+		ResourceHandler: NewResourceHandler[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input, *httpdto.Store{{.Pascal}}Request, *httpdto.Update{{.Pascal}}Request]("{{.Words}}", h, service),
+	}
+}
+`))
+
+var handlerTestTmpl = template.Must(template.New("handlerTest").Parse(`package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"{{.Module}}/internal/application"
+	applicationdto "{{.Module}}/internal/application/dto"
+	"{{.Module}}/internal/domain"
+	"{{.Module}}/internal/interface/http/response"
+	"github.com/stretchr/testify/require"
+)
+{{if .HasRequired}}
+// Ensures Store rejects an empty {{.Words}} payload without calling the application.
+func Test{{.Pascal}}HandlerStore_ValidationError(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	called := false
+	mockService := application.NewMockResourceService[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input]()
+	mockService.StoreFn = func(ctx context.Context, dto *applicationdto.Store{{.Pascal}}Input) (*domain.{{.Pascal}}, error) {
+		called = true
+		return nil, nil
+	}
+
+	h := New{{.Pascal}}Handler(NewHandler(srv), mockService)
+	app.Post("/{{.KebabPlural}}", h.Store())
+
+	req, err := http.NewRequest(http.MethodPost, "/{{.KebabPlural}}", bytes.NewReader(mustJSON(t, map[string]any{})))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.False(t, called)
+}
+{{end}}
+// Ensures Store parses the payload and returns the created {{.Words}} with 201 status.
+func Test{{.Pascal}}HandlerStore_Success(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	{{.Camel}}ID := uuid.New()
+	mockService := application.NewMockResourceService[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input]()
+	mockService.StoreFn = func(ctx context.Context, dto *applicationdto.Store{{.Pascal}}Input) (*domain.{{.Pascal}}, error) {
+		entity := dto.ToModel()
+		entity.ID = {{.Camel}}ID
+		return entity, nil
+	}
+
+	h := New{{.Pascal}}Handler(NewHandler(srv), mockService)
+	app.Post("/{{.KebabPlural}}", h.Store())
+
+	body := mustJSON(t, map[string]any{
+{{range .Fields}}		"{{.JSON}}": {{.SampleJSON}},
+{{end}}	})
+
+	req, err := http.NewRequest(http.MethodPost, "/{{.KebabPlural}}", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var got response.Response[domain.{{.Pascal}}]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.NotNil(t, got.Data)
+	require.Equal(t, {{.Camel}}ID, got.Data.ID)
+}
+
+// Ensures GetByID parses the id param and returns the {{.Words}}.
+func Test{{.Pascal}}HandlerGetByID_Success(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	{{.Camel}}ID := uuid.New()
+	mockService := application.NewMockResourceService[domain.{{.Pascal}}, *applicationdto.Store{{.Pascal}}Input, *applicationdto.Update{{.Pascal}}Input]()
+	mockService.GetByIDFn = func(ctx context.Context, id uuid.UUID, preloads []string) (*domain.{{.Pascal}}, error) {
+		require.Equal(t, {{.Camel}}ID, id)
+		return &domain.{{.Pascal}}{ID: id}, nil
+	}
+
+	h := New{{.Pascal}}Handler(NewHandler(srv), mockService)
+	app.Get("/{{.KebabPlural}}/:id", h.GetByID())
+
+	req, err := http.NewRequest(http.MethodGet, "/{{.KebabPlural}}/"+{{.Camel}}ID.String(), nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got domain.{{.Pascal}}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, {{.Camel}}ID, got.ID)
+}
+`))
+
+var migrationUpTmpl = template.Must(template.New("migrationUp").Parse(`CREATE TABLE IF NOT EXISTS {{.SnakePlural}} (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+{{range .Fields}}    {{.SQLColumn}},
+{{end}}    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+{{$table := .SnakePlural}}
+{{- range .Fields}}{{if .Unique}}
+-- Enforce uniqueness only on non-deleted records to support soft deletes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_{{$table}}_{{.Snake}}_active ON {{$table}} ({{.Snake}}) WHERE deleted_at IS NULL;
+{{- else if .Indexed}}
+CREATE INDEX IF NOT EXISTS idx_{{$table}}_{{.Snake}} ON {{$table}} ({{.Snake}});
+{{- end}}{{end}}
+CREATE INDEX IF NOT EXISTS idx_{{$table}}_deleted_at ON {{$table}} (deleted_at);
+`))
+
+var migrationDownTmpl = template.Must(template.New("migrationDown").Parse(`DROP INDEX IF EXISTS idx_{{.SnakePlural}}_deleted_at;
+{{- $table := .SnakePlural}}
+{{- range .ReversedFields}}{{if .Unique}}
+DROP INDEX IF EXISTS idx_{{$table}}_{{.Snake}}_active;
+{{- else if .Indexed}}
+DROP INDEX IF EXISTS idx_{{$table}}_{{.Snake}};
+{{- end}}{{end}}
+DROP TABLE IF EXISTS {{.SnakePlural}};
+`))
+
+// MySQL has no partial indexes, so unique fields are paired with the generated
+// active column, which is NULL for deleted rows and never collides.
+var migrationUpMySQLTmpl = template.Must(template.New("migrationUpMySQL").Parse(`CREATE TABLE IF NOT EXISTS {{.SnakePlural}} (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+{{range .Fields}}    {{.MySQLColumn}},
+{{end}}    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at DATETIME(6) NULL,
+{{- if .HasUnique}}
+    active TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) VIRTUAL,
+{{- end}}
+{{- $table := .SnakePlural}}
+{{- range .Fields}}{{if .Unique}}
+    UNIQUE INDEX idx_{{$table}}_{{.Snake}}_active ({{.Snake}}, active),
+{{- else if .Indexed}}
+    INDEX idx_{{$table}}_{{.Snake}} ({{.Snake}}),
+{{- end}}{{end}}
+    INDEX idx_{{$table}}_deleted_at (deleted_at)
+);
+`))
+
+var migrationDownMySQLTmpl = template.Must(template.New("migrationDownMySQL").Parse(`DROP TABLE IF EXISTS {{.SnakePlural}};
+`))
+
+var zodTmpl = template.Must(template.New("zod").Parse(`import { z } from 'zod'
+import { ZModel } from './utils.js'
+
+export const Z{{.Pascal}} = z
+	.object({
+{{range .Fields}}		{{.JSON}}: {{.ZodType}},
+{{end}}	})
+	.extend(ZModel.shape)
+
+export const ZStore{{.Pascal}}DTO = Z{{.Pascal}}.pick({
+{{range .Fields}}	{{.JSON}}: true,
+{{end}}})
+
+export const ZUpdate{{.Pascal}}DTO = ZStore{{.Pascal}}DTO.partial()
+`))
+
+var contractTmpl = template.Must(template.New("contract").Parse(`import { Z{{.Pascal}}, ZStore{{.Pascal}}DTO, ZUpdate{{.Pascal}}DTO } from '{{.ZodPackage}}'
+
+import { createResourceContract } from './resource.js'
+
+export const {{.JSON}}Contract = createResourceContract({
+	path: '/api/v1/{{.KebabPlural}}',
+	resource: '{{.Pascal}}',
+	resourcePlural: '{{.Plural}}',
+	schemas: {
+		entity: Z{{.Pascal}},
+		createDTO: ZStore{{.Pascal}}DTO,
+		updateDTO: ZUpdate{{.Pascal}}DTO,
+	},
+})
+`))
@@ -6,52 +6,91 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/validate"
 )
 
+// ComponentsFlow asks about the apps, ports, package manager and git in one
+// form, so Back returns to earlier answers.
 func ComponentsFlow(cfg *scaffold.ScaffoldConfiguration) error {
-	webForm := huh.NewForm(
+	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title(ui.IncludeWebTitle).
 				Description(ui.IncludeWebDescription).
 				Value(&cfg.IncludeWeb),
 		),
-	)
-	webForm.WithTheme(ui.HuhTheme())
-	webForm.WithWidth(80)
-	webForm.WithHeight(10)
-	webForm.WithOutput(os.Stdout)
-	if err := webForm.Run(); err != nil {
-		return err
-	}
-
-	dockerForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title(ui.IncludeDockerTitle).
 				Description(ui.IncludeDockerDesc).
 				Value(&cfg.IncludeDocker),
 		),
-	)
-	dockerForm.WithTheme(ui.HuhTheme())
-	dockerForm.WithWidth(80)
-	dockerForm.WithHeight(10)
-	dockerForm.WithOutput(os.Stdout)
-	if err := dockerForm.Run(); err != nil {
-		return err
-	}
-
-	gitForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title(ui.APIPortTitle).Description(ui.APIPortDesc).Validate(validate.Port).Value(&cfg.APIPort),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title(ui.WebPortTitle).Description(ui.WebPortDesc).Validate(validate.Port).Value(&cfg.WebPort),
+		).WithHideFunc(func() bool { return !cfg.IncludeWeb }),
+		huh.NewGroup(
+			huh.NewSelect[scaffold.PackageManager]().
+				Title(ui.PackageManagerTitle).
+				Description(ui.PackageManagerDesc).
+				Options(
+					huh.NewOption(ui.PackageBunLabel, scaffold.PackageBun),
+					huh.NewOption(ui.PackageNPMLabel, scaffold.PackageNPM),
+					huh.NewOption(ui.PackagePNPMLabel, scaffold.PackagePNPM),
+					huh.NewOption(ui.PackageYarnLabel, scaffold.PackageYarn),
+				).
+				Value(&cfg.PackageManager),
+		),
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title(ui.IncludeGitTitle).
 				Description(ui.IncludeGitDescription).
 				Value(&cfg.InitGit),
 		),
+		huh.NewGroup(
+			huh.NewInput().Title(ui.GitBranchTitle).Description(ui.GitBranchDesc).Validate(optional(validate.GitBranch)).Value(&cfg.Git.Branch),
+			huh.NewInput().Title(ui.GitRemoteTitle).Description(ui.GitRemoteDesc).Value(&cfg.Git.Remote),
+		).WithHideFunc(func() bool { return !cfg.InitGit }),
+	)
+	form.WithTheme(ui.HuhTheme())
+	form.WithWidth(80)
+	form.WithHeight(12)
+	form.WithOutput(os.Stdout)
+	return form.Run()
+}
+
+// GitIdentityFlow asks for the author of the initial commit when git has no
+// identity configured. Leaving both empty stages the files without a commit.
+func GitIdentityFlow(cfg *scaffold.ScaffoldConfiguration) error {
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().Title(ui.GitIdentityTitle).Description(ui.GitIdentityDesc),
+			huh.NewInput().Title(ui.GitAuthorNameTitle).Value(&cfg.Git.AuthorName),
+			huh.NewInput().Title(ui.GitAuthorEmailTitle).Validate(optional(validate.Email)).Value(&cfg.Git.AuthorEmail),
+		),
 	)
-	gitForm.WithTheme(ui.HuhTheme())
-	gitForm.WithWidth(80)
-	gitForm.WithHeight(10)
-	gitForm.WithOutput(os.Stdout)
-	return gitForm.Run()
+	form.WithTheme(ui.HuhTheme())
+	form.WithWidth(80)
+	form.WithHeight(14)
+	form.WithOutput(os.Stdout)
+	if err := form.Run(); err != nil {
+		return err
+	}
+	if cfg.Git.AuthorName == "" || cfg.Git.AuthorEmail == "" {
+		cfg.Git.AuthorName, cfg.Git.AuthorEmail = "", ""
+		cfg.Git.NoCommit = true
+	}
+	return nil
+}
+
+// optional skips check for empty input.
+func optional(check func(string) error) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		return check(value)
+	}
 }
@@ -0,0 +1,67 @@
+package prompts
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+)
+
+// conflictDiffLines caps the diff shown for one file so the form fits.
+const conflictDiffLines = 30
+
+// ConflictPolicy asks how to treat existing files in a non-empty destination.
+// An empty policy means the user cancelled.
+func ConflictPolicy(dest string) (scaffold.ConflictPolicy, error) {
+	policy := scaffold.ConflictPrompt
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[scaffold.ConflictPolicy]().
+				Title(fmt.Sprintf("%s\n%s", ui.ConflictPolicyTitle, dest)).
+				Description(ui.ConflictPolicyDescription).
+				Options(
+					huh.NewOption(ui.ConflictPromptLabel, scaffold.ConflictPrompt),
+					huh.NewOption(ui.ConflictSkipLabel, scaffold.ConflictSkip),
+					huh.NewOption(ui.ConflictBackupLabel, scaffold.ConflictBackup),
+					huh.NewOption(ui.ConflictOverwriteLabel, scaffold.ConflictOverwrite),
+					huh.NewOption(ui.ConflictCancelLabel, scaffold.ConflictPolicy("")),
+				).
+				Value(&policy),
+		),
+	)
+	form.WithTheme(ui.HuhTheme())
+	form.WithWidth(90)
+	form.WithHeight(14)
+	form.WithOutput(os.Stdout)
+
+	return policy, form.Run()
+}
+
+// ResolveConflict shows the diff for one conflicting file and asks what to do
+// with it.
+func ResolveConflict(conflict scaffold.Conflict, index, total int) (scaffold.Resolution, error) {
+	resolution := scaffold.ResolveKeep
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title(fmt.Sprintf("%s (%d/%d): %s", ui.ConflictFileTitle, index, total, conflict.Path)).
+				Description(ui.FormatDiff(conflict.Diff(), conflictDiffLines)),
+			huh.NewSelect[scaffold.Resolution]().
+				Title(ui.ConflictActionTitle).
+				Options(
+					huh.NewOption(ui.ConflictKeepLabel, scaffold.ResolveKeep),
+					huh.NewOption(ui.ConflictReplaceLabel, scaffold.ResolveReplace),
+					huh.NewOption(fmt.Sprintf(ui.ConflictWriteNewLabel, conflict.Path+".new"), scaffold.ResolveWriteNew),
+				).
+				Value(&resolution),
+		),
+	)
+	form.WithTheme(ui.HuhTheme())
+	form.WithWidth(110)
+	form.WithHeight(conflictDiffLines + 12)
+	form.WithOutput(os.Stdout)
+
+	return resolution, form.Run()
+}
@@ -8,8 +8,31 @@ import (
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
 )
 
+// DatabaseFlow asks for the database type, then for the connection details
+// in a second form, since their defaults depend on the type.
 func DatabaseFlow(cfg *scaffold.ScaffoldConfiguration) error {
-	cfg.DatabaseType = scaffold.DatabasePostgres
+	dbType := cfg.DatabaseType
+	typeForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[scaffold.DatabaseType]().
+				Title(ui.DBTypeTitle).
+				Description(ui.DBTypeDesc).
+				Options(
+					huh.NewOption(ui.DBPostgresLabel, scaffold.DatabasePostgres),
+					huh.NewOption(ui.DBMySQLLabel, scaffold.DatabaseMySQL),
+				).
+				Value(&dbType),
+		),
+	)
+	typeForm.WithTheme(ui.HuhTheme())
+	typeForm.WithWidth(80)
+	typeForm.WithHeight(10)
+	typeForm.WithOutput(os.Stdout)
+	if err := typeForm.Run(); err != nil {
+		return err
+	}
+	scaffold.SetDatabaseType(cfg, dbType)
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().Title(ui.DBHostTitle).Description(ui.DBHostDesc).Value(&cfg.DBConnection.Host),
@@ -1,9 +1,12 @@
 package prompts
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/huh"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
 )
 
@@ -12,24 +15,43 @@ type FlowChoice string
 const (
 	FlowBasic    FlowChoice = "basic"
 	FlowAdvanced FlowChoice = "advanced"
+	// FlowPreset applies a preset and then asks the basic questions.
+	FlowPreset FlowChoice = "preset"
 )
 
-func ChooseFlow() (FlowChoice, error) {
-	choice := FlowBasic
+// ChooseFlow asks how to configure the project. Choosing one of presets
+// returns FlowPreset and the preset's name.
+func ChooseFlow(presets []scaffold.Preset) (FlowChoice, string, error) {
+	choice := string(FlowBasic)
+	options := []huh.Option[string]{
+		huh.NewOption(ui.FlowBasicLabel, string(FlowBasic)),
+		huh.NewOption(ui.FlowAdvLabel, string(FlowAdvanced)),
+	}
+	for _, preset := range presets {
+		label := fmt.Sprintf(ui.FlowPresetLabel, preset.Name)
+		if preset.Description != "" {
+			label += " · " + preset.Description
+		}
+		options = append(options, huh.NewOption(label, string(FlowPreset)+":"+preset.Name))
+	}
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewSelect[FlowChoice]().
+			huh.NewSelect[string]().
 				Title(ui.FlowTitle).
 				Description(ui.FlowDescription).
-				Options(
-					huh.NewOption(ui.FlowBasicLabel, FlowBasic),
-					huh.NewOption(ui.FlowAdvLabel, FlowAdvanced),
-				).Value(&choice),
+				Options(options...).
+				Value(&choice),
 		),
 	)
 	form.WithTheme(ui.HuhTheme())
 	form.WithWidth(80)
-	form.WithHeight(12)
+	form.WithHeight(12 + len(presets))
 	form.WithOutput(os.Stdout)
-	return choice, form.Run()
+	if err := form.Run(); err != nil {
+		return FlowBasic, "", err
+	}
+	if name, ok := strings.CutPrefix(choice, string(FlowPreset)+":"); ok {
+		return FlowPreset, name, nil
+	}
+	return FlowChoice(choice), "", nil
 }
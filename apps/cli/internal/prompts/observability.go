@@ -17,6 +17,7 @@ func ObservabilityFlow(cfg *scaffold.ScaffoldConfiguration) error {
 				Options(
 					huh.NewOption(ui.ObservabilityNoneLabel, scaffold.ObservabilityNone),
 					huh.NewOption(ui.ObservabilityGrafanaOSSLabel, scaffold.ObservabilityGrafanaOSS),
+					huh.NewOption(ui.ObservabilityOTLPLabel, scaffold.ObservabilityOTLP),
 				).
 				Value(&cfg.Observability),
 		),
@@ -0,0 +1,39 @@
+package prompts
+
+import (
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/validate"
+)
+
+// ProjectFlow asks for the metadata written to LICENSE, package.json, the
+// README and the Docker image labels.
+func ProjectFlow(cfg *scaffold.ScaffoldConfiguration) error {
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title(ui.DescriptionTitle).Description(ui.DescriptionDesc).Validate(validate.MetadataText).Value(&cfg.Metadata.Description),
+			huh.NewInput().Title(ui.AuthorTitle).Description(ui.AuthorDesc).Validate(validate.MetadataText).Value(&cfg.Metadata.Author),
+			huh.NewInput().Title(ui.HomepageTitle).Description(ui.HomepageDesc).Validate(optional(validate.Homepage)).Value(&cfg.Metadata.Homepage),
+		),
+		huh.NewGroup(
+			huh.NewSelect[scaffold.License]().
+				Title(ui.LicenseTitle).
+				Description(ui.LicenseDesc).
+				Options(
+					huh.NewOption(ui.LicenseMITLabel, scaffold.LicenseMIT),
+					huh.NewOption(ui.LicenseApacheLabel, scaffold.LicenseApache),
+					huh.NewOption(ui.LicenseBSD3Label, scaffold.LicenseBSD3),
+					huh.NewOption(ui.LicenseProprietaryLabel, scaffold.LicenseProprietary),
+				).
+				Value(&cfg.Metadata.License),
+		),
+	)
+	form.WithTheme(ui.HuhTheme())
+	form.WithWidth(80)
+	form.WithHeight(16)
+	form.WithOutput(os.Stdout)
+	return form.Run()
+}
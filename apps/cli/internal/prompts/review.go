@@ -3,6 +3,7 @@ package prompts
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
@@ -14,28 +15,51 @@ type ReviewAction string
 
 const (
 	ReviewGenerate ReviewAction = "generate"
-	ReviewEdit     ReviewAction = "edit"
-	ReviewCancel   ReviewAction = "cancel"
+	// ReviewEdit starts over from the flow choice.
+	ReviewEdit   ReviewAction = "edit"
+	ReviewCancel ReviewAction = "cancel"
+
+	// Edit one section and return to the review.
+	ReviewEditBasics        ReviewAction = "edit-basics"
+	ReviewEditProject       ReviewAction = "edit-project"
+	ReviewEditDestination   ReviewAction = "edit-destination"
+	ReviewEditComponents    ReviewAction = "edit-components"
+	ReviewEditDatabase      ReviewAction = "edit-database"
+	ReviewEditStorage       ReviewAction = "edit-storage"
+	ReviewEditObservability ReviewAction = "edit-observability"
 )
 
+// reviewSection is a part of the configuration that one flow asks about.
+type reviewSection struct {
+	edit  ReviewAction
+	label string
+	value string
+}
+
 func ReviewConfig(cfg scaffold.ScaffoldConfiguration) (ReviewAction, error) {
-	summary := reviewSummary(cfg)
+	sections := reviewSections(cfg)
+
+	options := []huh.Option[ReviewAction]{huh.NewOption(ui.ReviewGenerateLabel, ReviewGenerate)}
+	for _, section := range sections {
+		options = append(options, huh.NewOption(fmt.Sprintf(ui.ReviewEditSectionLabel, section.label), section.edit))
+	}
+	options = append(options,
+		huh.NewOption(ui.ReviewEditLabel, ReviewEdit),
+		huh.NewOption(ui.ReviewCancelLabel, ReviewCancel),
+	)
 
 	action := ReviewGenerate
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewNote().Title(ui.ReviewTitle).Description(summary),
+			huh.NewNote().Title(ui.ReviewTitle).Description(reviewSummary(sections)),
 			huh.NewSelect[ReviewAction]().Title(ui.ReviewActionTitle).
-				Options(
-					huh.NewOption(ui.ReviewGenerateLabel, ReviewGenerate),
-					huh.NewOption(ui.ReviewEditLabel, ReviewEdit),
-					huh.NewOption(ui.ReviewCancelLabel, ReviewCancel),
-				).Value(&action),
+				Options(options...).
+				Value(&action),
 		),
 	)
 	form.WithTheme(ui.HuhTheme())
 	form.WithWidth(80)
-	form.WithHeight(20)
+	form.WithHeight(28)
 	form.WithOutput(os.Stdout)
 
 	return action, form.Run()
@@ -48,18 +72,62 @@ func resolveDisplayDestination(cfg scaffold.ScaffoldConfiguration) string {
 	return cfg.Destination
 }
 
-func reviewSummary(cfg scaffold.ScaffoldConfiguration) string {
-	return fmt.Sprintf(
-		"%s\nProject name: %s\nDestination: %s\nModule: %s\nWeb: %t\nDocker: %t\nGit: %t\nDatabase: %s\nStorage: %s\nObservability: %s\n",
-		ui.ReviewSummaryHeading,
-		cfg.ProjectName,
-		resolveDisplayDestination(cfg),
-		cfg.ModulePath,
-		cfg.IncludeWeb,
-		cfg.IncludeDocker,
-		cfg.InitGit,
-		cfg.DatabaseType,
-		cfg.Storage.Type,
-		cfg.Observability,
-	)
+func reviewSections(cfg scaffold.ScaffoldConfiguration) []reviewSection {
+	components := []string{"API"}
+	if cfg.IncludeWeb {
+		components = append(components, "web")
+	}
+	if cfg.IncludeDocker {
+		components = append(components, "Docker")
+	}
+	if cfg.InitGit {
+		git := "git"
+		if cfg.Git.Branch != "" {
+			git += " (" + cfg.Git.Branch + ")"
+		}
+		components = append(components, git)
+	}
+	ports := "API " + cfg.APIPort
+	if cfg.IncludeWeb {
+		ports += ", web " + cfg.WebPort
+	}
+
+	db := cfg.DBConnection
+	storage := string(cfg.Storage.Type)
+	switch {
+	case cfg.Storage.Type == scaffold.StorageLocal && cfg.Storage.Local != nil:
+		storage += " (" + cfg.Storage.Local.Path + ")"
+	case cfg.Storage.Type == scaffold.StorageS3 && cfg.Storage.S3 != nil:
+		storage += " (" + cfg.Storage.S3.Bucket + " in " + cfg.Storage.S3.Region + ")"
+	}
+
+	project := string(cfg.Metadata.License)
+	if cfg.Metadata.Author != "" {
+		project += " · " + cfg.Metadata.Author
+	}
+	if cfg.Metadata.Homepage != "" {
+		project += " · " + cfg.Metadata.Homepage
+	}
+
+	return []reviewSection{
+		{ReviewEditBasics, "Basics", cfg.ProjectName + " · " + cfg.ModulePath},
+		{ReviewEditProject, "Project", project},
+		{ReviewEditDestination, "Destination", resolveDisplayDestination(cfg)},
+		{ReviewEditComponents, "Components", fmt.Sprintf("%s · ports %s · %s", strings.Join(components, ", "), ports, cfg.PackageManager)},
+		{ReviewEditDatabase, "Database", fmt.Sprintf("%s · %s@%s:%s/%s", cfg.DatabaseType, db.User, db.Host, db.Port, db.Name)},
+		{ReviewEditStorage, "Storage", storage},
+		{ReviewEditObservability, "Observability", string(cfg.Observability)},
+	}
+}
+
+func reviewSummary(sections []reviewSection) string {
+	width := 0
+	for _, section := range sections {
+		width = max(width, len(section.label))
+	}
+	lines := []string{ui.ReviewSummaryHeading}
+	for _, section := range sections {
+		lines = append(lines, fmt.Sprintf("%-*s %s", width+1, section.label+":", section.value))
+	}
+	return strings.Join(lines, "\n") + "\n"
 }
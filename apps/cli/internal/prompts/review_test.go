@@ -2,6 +2,7 @@ package prompts
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
@@ -37,3 +38,40 @@ func TestResolveDisplayDestinationResolvesProvidedBasePath(t *testing.T) {
 		t.Fatalf("expected %q, got %q", want, got)
 	}
 }
+
+func TestReviewSummaryListsEverySection(t *testing.T) {
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.IncludeWeb = false
+	cfg.Git.Branch = "trunk"
+	cfg.Metadata.Author = "Acme"
+
+	sections := reviewSections(cfg)
+	var edits []ReviewAction
+	for _, section := range sections {
+		edits = append(edits, section.edit)
+	}
+	want := []ReviewAction{ReviewEditBasics, ReviewEditProject, ReviewEditDestination, ReviewEditComponents, ReviewEditDatabase, ReviewEditStorage, ReviewEditObservability}
+	if len(edits) != len(want) {
+		t.Fatalf("expected sections %v, got %v", want, edits)
+	}
+	for i := range want {
+		if edits[i] != want[i] {
+			t.Fatalf("expected sections %v, got %v", want, edits)
+		}
+	}
+
+	summary := reviewSummary(sections)
+	for _, line := range []string{
+		"Basics:        demo · github.com/acme/demo",
+		"Project:       MIT · Acme",
+		"Components:    API, Docker, git (trunk) · ports API 8080 · bun",
+		"Database:      postgres · postgres@localhost:5432/",
+		"Storage:       local (storage)",
+	} {
+		if !strings.Contains(summary, line) {
+			t.Fatalf("expected %q in summary:\n%s", line, summary)
+		}
+	}
+}
@@ -8,11 +8,22 @@ import (
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/ui"
 )
 
+// StorageFlow asks for the storage type and its settings in one form. Only
+// the settings of the chosen type are kept.
 func StorageFlow(cfg *scaffold.ScaffoldConfiguration) error {
 	choice := string(cfg.Storage.Type)
 	if choice == "" {
 		choice = string(scaffold.StorageLocal)
 	}
+	local := cfg.Storage.Local
+	if local == nil {
+		local = &scaffold.LocalStorageConfig{Path: "storage"}
+	}
+	s3 := cfg.Storage.S3
+	if s3 == nil {
+		s3 = &scaffold.S3Config{}
+	}
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
@@ -23,54 +34,34 @@ func StorageFlow(cfg *scaffold.ScaffoldConfiguration) error {
 					huh.NewOption(ui.StorageS3Label, string(scaffold.StorageS3)),
 				).Value(&choice),
 		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title(ui.LocalPathTitle).
+				Description(ui.LocalPathDesc).
+				Value(&local.Path),
+		).WithHideFunc(func() bool { return choice != string(scaffold.StorageLocal) }),
+		huh.NewGroup(
+			huh.NewInput().Title(ui.S3EndpointTitle).Value(&s3.Endpoint),
+			huh.NewInput().Title(ui.S3RegionTitle).Value(&s3.Region),
+			huh.NewInput().Title(ui.S3BucketTitle).Value(&s3.Bucket),
+			huh.NewInput().Title(ui.S3AccessTitle).Value(&s3.AccessKey),
+			huh.NewInput().Title(ui.S3SecretTitle).Value(&s3.SecretKey),
+		).WithHideFunc(func() bool { return choice != string(scaffold.StorageS3) }),
 	)
 	form.WithTheme(ui.HuhTheme())
 	form.WithWidth(80)
-	form.WithHeight(10)
+	form.WithHeight(16)
 	form.WithOutput(os.Stdout)
 	if err := form.Run(); err != nil {
 		return err
 	}
 
 	cfg.Storage.Type = scaffold.StorageType(choice)
-	if cfg.Storage.Type == scaffold.StorageLocal {
-		if cfg.Storage.Local == nil {
-			cfg.Storage.Local = &scaffold.LocalStorageConfig{Path: "storage"}
-		}
-		localForm := huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().
-					Title(ui.LocalPathTitle).
-					Description(ui.LocalPathDesc).
-					Value(&cfg.Storage.Local.Path),
-			),
-		)
-		localForm.WithTheme(ui.HuhTheme())
-		localForm.WithWidth(80)
-		localForm.WithHeight(8)
-		localForm.WithOutput(os.Stdout)
-		return localForm.Run()
-	}
-
-	if cfg.Storage.Type == scaffold.StorageS3 {
-		if cfg.Storage.S3 == nil {
-			cfg.Storage.S3 = &scaffold.S3Config{}
-		}
-		s3Form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().Title(ui.S3EndpointTitle).Value(&cfg.Storage.S3.Endpoint),
-				huh.NewInput().Title(ui.S3RegionTitle).Value(&cfg.Storage.S3.Region),
-				huh.NewInput().Title(ui.S3BucketTitle).Value(&cfg.Storage.S3.Bucket),
-				huh.NewInput().Title(ui.S3AccessTitle).Value(&cfg.Storage.S3.AccessKey),
-				huh.NewInput().Title(ui.S3SecretTitle).Value(&cfg.Storage.S3.SecretKey),
-			),
-		)
-		s3Form.WithTheme(ui.HuhTheme())
-		s3Form.WithWidth(80)
-		s3Form.WithHeight(16)
-		s3Form.WithOutput(os.Stdout)
-		return s3Form.Run()
+	switch cfg.Storage.Type {
+	case scaffold.StorageLocal:
+		cfg.Storage.Local = local
+	case scaffold.StorageS3:
+		cfg.Storage.S3 = s3
 	}
-
 	return nil
 }
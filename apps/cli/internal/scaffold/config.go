@@ -6,14 +6,24 @@ type PackageManager string
 
 type StorageType string
 type ObservabilityProvider string
+type License string
 
 const (
 	DatabasePostgres        DatabaseType          = "postgres"
+	DatabaseMySQL           DatabaseType          = "mysql"
 	PackageBun              PackageManager        = "bun"
+	PackageNPM              PackageManager        = "npm"
+	PackagePNPM             PackageManager        = "pnpm"
+	PackageYarn             PackageManager        = "yarn"
 	StorageLocal            StorageType           = "local"
 	StorageS3               StorageType           = "s3"
 	ObservabilityNone       ObservabilityProvider = "none"
 	ObservabilityGrafanaOSS ObservabilityProvider = "grafana-oss"
+	ObservabilityOTLP       ObservabilityProvider = "otlp"
+	LicenseMIT              License               = "MIT"
+	LicenseApache           License               = "Apache-2.0"
+	LicenseBSD3             License               = "BSD-3-Clause"
+	LicenseProprietary      License               = "proprietary"
 )
 
 type DBConnection struct {
@@ -43,6 +53,15 @@ type StorageConfig struct {
 	Local *LocalStorageConfig
 }
 
+// ProjectMetadata describes the project in its LICENSE, package.json files,
+// README, Docker image labels and telemetry.
+type ProjectMetadata struct {
+	Description string
+	Author      string
+	Homepage    string
+	License     License
+}
+
 type ScaffoldConfiguration struct {
 	ProjectName    string
 	Destination    string
@@ -53,7 +72,20 @@ type ScaffoldConfiguration struct {
 	PackageManager PackageManager
 	IncludeDocker  bool
 	InitGit        bool
-	Storage        StorageConfig
-	Observability  ObservabilityProvider
-	UseDefaults    bool
+	// Git configures the repository created when InitGit is set.
+	Git           GitOptions
+	Storage       StorageConfig
+	Observability ObservabilityProvider
+	// APIPort and WebPort are the local ports of the apps; URLs that point
+	// from one app to the other are derived from them.
+	APIPort     string
+	WebPort     string
+	UseDefaults bool
+	Metadata    ProjectMetadata
+	// Template is the template source spec; empty means the embedded template.
+	Template string
+	// Features selects optional template features that have no condition.
+	Features []string
+	// Secrets are filled in by GenerateSecrets right before generation.
+	Secrets Secrets
 }
@@ -0,0 +1,349 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ConfigFileVersion = 1
+
+// ConfigFile is the versioned, declarative form of ScaffoldConfiguration used
+// by `gokickstart new --config` and `--save-config`. Unset keys keep the value
+// of the configuration they are applied to.
+type ConfigFile struct {
+	Version        int                 `yaml:"version" json:"version"`
+	Description    string              `yaml:"description,omitempty" json:"description,omitempty"`
+	Name           string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Module         string              `yaml:"module,omitempty" json:"module,omitempty"`
+	Destination    string              `yaml:"destination,omitempty" json:"destination,omitempty"`
+	Project        *ProjectConfigFile  `yaml:"project,omitempty" json:"project,omitempty"`
+	Web            *bool               `yaml:"web,omitempty" json:"web,omitempty"`
+	Docker         *bool               `yaml:"docker,omitempty" json:"docker,omitempty"`
+	Git            *bool               `yaml:"git,omitempty" json:"git,omitempty"`
+	GitOptions     *GitConfigFile      `yaml:"gitOptions,omitempty" json:"gitOptions,omitempty"`
+	PackageManager string              `yaml:"packageManager,omitempty" json:"packageManager,omitempty"`
+	Observability  string              `yaml:"observability,omitempty" json:"observability,omitempty"`
+	Ports          *PortsConfigFile    `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Database       *DatabaseConfigFile `yaml:"database,omitempty" json:"database,omitempty"`
+	Storage        *StorageConfigFile  `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Template       string              `yaml:"template,omitempty" json:"template,omitempty"`
+	Features       []string            `yaml:"features,omitempty" json:"features,omitempty"`
+}
+
+// ProjectConfigFile holds the project metadata. It is separate from the
+// top-level description, which describes the config file itself.
+type ProjectConfigFile struct {
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Author      string `yaml:"author,omitempty" json:"author,omitempty"`
+	Homepage    string `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	License     string `yaml:"license,omitempty" json:"license,omitempty"`
+}
+
+type PortsConfigFile struct {
+	API string `yaml:"api,omitempty" json:"api,omitempty"`
+	Web string `yaml:"web,omitempty" json:"web,omitempty"`
+}
+
+type GitConfigFile struct {
+	Branch        string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Remote        string `yaml:"remote,omitempty" json:"remote,omitempty"`
+	AuthorName    string `yaml:"authorName,omitempty" json:"authorName,omitempty"`
+	AuthorEmail   string `yaml:"authorEmail,omitempty" json:"authorEmail,omitempty"`
+	CommitMessage string `yaml:"commitMessage,omitempty" json:"commitMessage,omitempty"`
+	// Commit set to false stages the generated files without committing.
+	Commit *bool `yaml:"commit,omitempty" json:"commit,omitempty"`
+}
+
+type DatabaseConfigFile struct {
+	Type     string `yaml:"type,omitempty" json:"type,omitempty"`
+	Host     string `yaml:"host,omitempty" json:"host,omitempty"`
+	Port     string `yaml:"port,omitempty" json:"port,omitempty"`
+	User     string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	Name     string `yaml:"name,omitempty" json:"name,omitempty"`
+	SSLMode  string `yaml:"sslMode,omitempty" json:"sslMode,omitempty"`
+}
+
+type StorageConfigFile struct {
+	Type  string                  `yaml:"type,omitempty" json:"type,omitempty"`
+	Local *LocalStorageConfigFile `yaml:"local,omitempty" json:"local,omitempty"`
+	S3    *S3ConfigFile           `yaml:"s3,omitempty" json:"s3,omitempty"`
+}
+
+type LocalStorageConfigFile struct {
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+type S3ConfigFile struct {
+	Endpoint  string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	Region    string `yaml:"region,omitempty" json:"region,omitempty"`
+	Bucket    string `yaml:"bucket,omitempty" json:"bucket,omitempty"`
+	AccessKey string `yaml:"accessKey,omitempty" json:"accessKey,omitempty"`
+	SecretKey string `yaml:"secretKey,omitempty" json:"secretKey,omitempty"`
+}
+
+var envRefRe = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// LoadConfigFile reads a YAML (.yaml/.yml) or JSON (.json) config file.
+// Unknown keys are rejected so typos surface instead of being ignored.
+func LoadConfigFile(path string) (ConfigFile, error) {
+	var file ConfigFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return file, fmt.Errorf("parse %s: %w", path, err)
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return file, fmt.Errorf("parse %s: %w", path, err)
+		}
+	default:
+		return file, fmt.Errorf("unsupported config file extension %q (use .yaml, .yml or .json)", filepath.Ext(path))
+	}
+
+	if file.Version == 0 {
+		return file, fmt.Errorf("%s: version: is required (current version is %d)", path, ConfigFileVersion)
+	}
+	if file.Version != ConfigFileVersion {
+		return file, fmt.Errorf("%s: version: unsupported config version %d (supported: %d)", path, file.Version, ConfigFileVersion)
+	}
+	return file, nil
+}
+
+// Apply overlays the keys set in the file onto cfg. String values written as
+// ${VAR} are read from the environment so secrets can stay out of the file.
+func (f ConfigFile) Apply(cfg *ScaffoldConfiguration) {
+	setString(&cfg.ProjectName, f.Name)
+	setString(&cfg.ModulePath, f.Module)
+	setString(&cfg.Destination, f.Destination)
+	setString(&cfg.Template, f.Template)
+	if len(f.Features) > 0 {
+		cfg.Features = append([]string(nil), f.Features...)
+	}
+	if p := f.Project; p != nil {
+		setString(&cfg.Metadata.Description, p.Description)
+		setString(&cfg.Metadata.Author, p.Author)
+		setString(&cfg.Metadata.Homepage, p.Homepage)
+		if v := expandEnvRef(p.License); v != "" {
+			cfg.Metadata.License = ParseLicense(v)
+		}
+	}
+	if f.Web != nil {
+		cfg.IncludeWeb = *f.Web
+	}
+	if f.Docker != nil {
+		cfg.IncludeDocker = *f.Docker
+	}
+	if f.Git != nil {
+		cfg.InitGit = *f.Git
+	}
+	if v := expandEnvRef(f.PackageManager); v != "" {
+		cfg.PackageManager = PackageManager(v)
+	}
+	if v := expandEnvRef(f.Observability); v != "" {
+		cfg.Observability = ObservabilityProvider(v)
+	}
+	if g := f.GitOptions; g != nil {
+		setString(&cfg.Git.Branch, g.Branch)
+		setString(&cfg.Git.Remote, g.Remote)
+		setString(&cfg.Git.AuthorName, g.AuthorName)
+		setString(&cfg.Git.AuthorEmail, g.AuthorEmail)
+		setString(&cfg.Git.CommitMessage, g.CommitMessage)
+		if g.Commit != nil {
+			cfg.Git.NoCommit = !*g.Commit
+		}
+	}
+	if p := f.Ports; p != nil {
+		setString(&cfg.APIPort, p.API)
+		setString(&cfg.WebPort, p.Web)
+	}
+
+	if db := f.Database; db != nil {
+		if v := expandEnvRef(db.Type); v != "" {
+			SetDatabaseType(cfg, DatabaseType(v))
+		}
+		setString(&cfg.DBConnection.Host, db.Host)
+		setString(&cfg.DBConnection.Port, db.Port)
+		setString(&cfg.DBConnection.User, db.User)
+		setString(&cfg.DBConnection.Password, db.Password)
+		setString(&cfg.DBConnection.Name, db.Name)
+		setString(&cfg.DBConnection.SSLMode, db.SSLMode)
+	}
+
+	if st := f.Storage; st != nil {
+		if v := expandEnvRef(st.Type); v != "" {
+			cfg.Storage.Type = StorageType(v)
+		}
+		if st.Local != nil {
+			if cfg.Storage.Local == nil {
+				cfg.Storage.Local = &LocalStorageConfig{}
+			}
+			setString(&cfg.Storage.Local.Path, st.Local.Path)
+		}
+		if st.S3 != nil {
+			if cfg.Storage.S3 == nil {
+				cfg.Storage.S3 = &S3Config{}
+			}
+			setString(&cfg.Storage.S3.Endpoint, st.S3.Endpoint)
+			setString(&cfg.Storage.S3.Region, st.S3.Region)
+			setString(&cfg.Storage.S3.Bucket, st.S3.Bucket)
+			setString(&cfg.Storage.S3.AccessKey, st.S3.AccessKey)
+			setString(&cfg.Storage.S3.SecretKey, st.S3.SecretKey)
+		}
+	}
+}
+
+// ConfigFileFrom captures cfg as a config file. The destination is left out so
+// the same file can be reused from any working directory.
+func ConfigFileFrom(cfg ScaffoldConfiguration) ConfigFile {
+	web, docker, git := cfg.IncludeWeb, cfg.IncludeDocker, cfg.InitGit
+	file := ConfigFile{
+		Version: ConfigFileVersion,
+		Name:    cfg.ProjectName,
+		Module:  cfg.ModulePath,
+		Project: &ProjectConfigFile{
+			Description: cfg.Metadata.Description,
+			Author:      cfg.Metadata.Author,
+			Homepage:    cfg.Metadata.Homepage,
+			License:     string(cfg.Metadata.License),
+		},
+		Web:            &web,
+		Docker:         &docker,
+		Git:            &git,
+		PackageManager: string(cfg.PackageManager),
+		Observability:  string(cfg.Observability),
+		Ports:          &PortsConfigFile{API: cfg.APIPort, Web: cfg.WebPort},
+		Database: &DatabaseConfigFile{
+			Type:     string(cfg.DatabaseType),
+			Host:     cfg.DBConnection.Host,
+			Port:     cfg.DBConnection.Port,
+			User:     cfg.DBConnection.User,
+			Password: cfg.DBConnection.Password,
+			Name:     cfg.DBConnection.Name,
+			SSLMode:  cfg.DBConnection.SSLMode,
+		},
+		Storage:  &StorageConfigFile{Type: string(cfg.Storage.Type)},
+		Template: cfg.Template,
+		Features: cfg.Features,
+	}
+	if cfg.Git != (GitOptions{}) {
+		commit := !cfg.Git.NoCommit
+		file.GitOptions = &GitConfigFile{
+			Branch:        cfg.Git.Branch,
+			Remote:        cfg.Git.Remote,
+			AuthorName:    cfg.Git.AuthorName,
+			AuthorEmail:   cfg.Git.AuthorEmail,
+			CommitMessage: cfg.Git.CommitMessage,
+			Commit:        &commit,
+		}
+	}
+	if cfg.Storage.Type == StorageLocal && cfg.Storage.Local != nil {
+		file.Storage.Local = &LocalStorageConfigFile{Path: cfg.Storage.Local.Path}
+	}
+	if cfg.Storage.Type == StorageS3 && cfg.Storage.S3 != nil {
+		file.Storage.S3 = &S3ConfigFile{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			Region:    cfg.Storage.S3.Region,
+			Bucket:    cfg.Storage.S3.Bucket,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+		}
+	}
+	return file
+}
+
+// Environment variable references SaveConfigFile writes instead of
+// credentials. They name the variables of the matching `new` flags, so a CI
+// job that sets them reproduces the project from the committed file.
+const (
+	dbPasswordRef  = "${GOKICKSTART_DB_PASSWORD}"
+	s3AccessKeyRef = "${GOKICKSTART_S3_ACCESS_KEY}"
+	s3SecretKeyRef = "${GOKICKSTART_S3_SECRET_KEY}"
+)
+
+// SaveConfigFile writes cfg to path as YAML or JSON depending on the extension.
+// The database password and S3 keys are written as ${GOKICKSTART_*}
+// references, because the file is meant to be shared.
+func SaveConfigFile(path string, cfg ScaffoldConfiguration) error {
+	file := replaceSecrets(ConfigFileFrom(cfg), dbPasswordRef, s3AccessKeyRef, s3SecretKeyRef)
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err = json.MarshalIndent(file, "", "  ")
+		data = append(data, '\n')
+	case ".yaml", ".yml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(file)
+		data = buf.Bytes()
+	default:
+		return fmt.Errorf("unsupported config file extension %q (use .yaml, .yml or .json)", filepath.Ext(path))
+	}
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// replaceSecrets returns file with the database password and the S3 keys
+// that are set replaced by the given values.
+func replaceSecrets(file ConfigFile, dbPassword, s3AccessKey, s3SecretKey string) ConfigFile {
+	replace := func(target *string, value string) {
+		if *target != "" {
+			*target = value
+		}
+	}
+	if file.Database != nil {
+		db := *file.Database
+		replace(&db.Password, dbPassword)
+		file.Database = &db
+	}
+	if file.Storage != nil && file.Storage.S3 != nil {
+		storage := *file.Storage
+		s3 := *storage.S3
+		replace(&s3.AccessKey, s3AccessKey)
+		replace(&s3.SecretKey, s3SecretKey)
+		storage.S3 = &s3
+		file.Storage = &storage
+	}
+	return file
+}
+
+// setString sets target to value unless value is empty or the placeholder a
+// manifest records for a redacted secret, which keeps the current value.
+func setString(target *string, value string) {
+	if v := expandEnvRef(value); v != "" && v != redacted {
+		*target = v
+	}
+}
+
+func expandEnvRef(value string) string {
+	if match := envRefRe.FindStringSubmatch(value); match != nil {
+		return os.Getenv(match[1])
+	}
+	return value
+}
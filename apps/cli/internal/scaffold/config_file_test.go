@@ -0,0 +1,189 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	t.Setenv("KICKSTART_TEST_SECRET", "from-env")
+	path := writeConfig(t, "kickstart.yaml", `version: 1
+name: demo
+module: github.com/acme/demo
+web: false
+project:
+  author: Acme Inc.
+  license: bsd-3
+database:
+  host: db.internal
+  password: ${KICKSTART_TEST_SECRET}
+storage:
+  type: s3
+  s3:
+    endpoint: https://s3.example.com
+    region: us-east-1
+    bucket: demo
+    accessKey: key
+    secretKey: ${KICKSTART_TEST_SECRET}
+`)
+	file, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	file.Apply(&cfg)
+	if cfg.ProjectName != "demo" || cfg.ModulePath != "github.com/acme/demo" {
+		t.Fatalf("unexpected name/module: %s %s", cfg.ProjectName, cfg.ModulePath)
+	}
+	if cfg.IncludeWeb {
+		t.Fatalf("expected web to be disabled")
+	}
+	if cfg.Metadata != (ProjectMetadata{Author: "Acme Inc.", License: LicenseBSD3}) {
+		t.Fatalf("unexpected project metadata: %+v", cfg.Metadata)
+	}
+	if !cfg.IncludeDocker {
+		t.Fatalf("expected unset docker key to keep the default")
+	}
+	if cfg.DBConnection.Host != "db.internal" || cfg.DBConnection.Port != "5432" {
+		t.Fatalf("unexpected db connection: %+v", cfg.DBConnection)
+	}
+	if cfg.DBConnection.Password != "from-env" || cfg.Storage.S3.SecretKey != "from-env" {
+		t.Fatalf("expected ${VAR} values to be read from the environment")
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	path := writeConfig(t, "kickstart.json", `{"version": 1, "name": "demo", "observability": "grafana-oss"}`)
+	file, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg := DefaultConfig()
+	file.Apply(&cfg)
+	if cfg.Observability != ObservabilityGrafanaOSS {
+		t.Fatalf("expected grafana-oss, got %s", cfg.Observability)
+	}
+}
+
+func TestLoadConfigFileRejectsInvalidFiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"unknown key", "c.yaml", "version: 1\nnmae: demo\n", "nmae"},
+		{"unknown json key", "c.json", `{"version": 1, "nmae": "demo"}`, "nmae"},
+		{"missing version", "c.yaml", "name: demo\n", "version"},
+		{"future version", "c.yaml", "version: 2\n", "unsupported config version 2"},
+		{"unknown extension", "c.toml", "version = 1\n", "unsupported config file extension"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := LoadConfigFile(writeConfig(t, c.file, c.content))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("expected error containing %q, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestSaveConfigFileRoundTrip(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = "/somewhere/demo"
+	cfg.IncludeDocker = false
+	cfg.Metadata = ProjectMetadata{Description: "Demo app", Author: "Acme", Homepage: "https://acme.dev", License: LicenseProprietary}
+	cfg.Git = GitOptions{Branch: "trunk", Remote: "git@github.com:acme/demo.git", NoCommit: true}
+	cfg.Storage = StorageConfig{Type: StorageS3, S3: &S3Config{
+		Endpoint: "https://s3.example.com", Region: "us-east-1", Bucket: "demo", AccessKey: "key", SecretKey: "secret",
+	}}
+
+	cfg.DBConnection.Password = "db-secret"
+	t.Setenv("GOKICKSTART_DB_PASSWORD", "db-secret")
+	t.Setenv("GOKICKSTART_S3_ACCESS_KEY", "key")
+	t.Setenv("GOKICKSTART_S3_SECRET_KEY", "secret")
+
+	for _, name := range []string{"kickstart.yaml", "kickstart.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := SaveConfigFile(path, cfg); err != nil {
+				t.Fatalf("save config: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read config: %v", err)
+			}
+			for _, secret := range []string{"db-secret", `"key"`, "key\n", "secret\n", `"secret"`} {
+				if strings.Contains(string(data), secret) {
+					t.Fatalf("expected credentials to be written as references, found %q in:\n%s", secret, data)
+				}
+			}
+			if !strings.Contains(string(data), "${GOKICKSTART_S3_SECRET_KEY}") {
+				t.Fatalf("expected an environment reference for the S3 secret key:\n%s", data)
+			}
+			file, err := LoadConfigFile(path)
+			if err != nil {
+				t.Fatalf("load config: %v", err)
+			}
+			if file.Destination != "" {
+				t.Fatalf("expected destination to be left out, got %q", file.Destination)
+			}
+
+			got := DefaultConfig()
+			file.Apply(&got)
+			got.Destination = cfg.Destination
+			if got.ProjectName != cfg.ProjectName || got.IncludeDocker != cfg.IncludeDocker || got.Git != cfg.Git || got.Metadata != cfg.Metadata || *got.Storage.S3 != *cfg.Storage.S3 ||
+				got.DBConnection.Password != cfg.DBConnection.Password {
+				t.Fatalf("round trip mismatch: %+v", got)
+			}
+		})
+	}
+}
+
+func TestValidateConfigReportsEveryField(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ModulePath = ""
+	cfg.Observability = "bogus"
+	cfg.DBConnection.Port = "abc"
+	cfg.APIPort = "70000"
+	cfg.Git = GitOptions{Branch: "my branch", AuthorEmail: "nobody"}
+	cfg.Metadata = ProjectMetadata{Description: `say "hi"`, Homepage: "acme.dev", License: "GPL"}
+	cfg.Storage = StorageConfig{Type: StorageS3, S3: &S3Config{Endpoint: "https://s3.example.com"}}
+
+	err := ValidateConfig(cfg)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("expected ConfigError, got %v", err)
+	}
+
+	fields := map[string]string{}
+	for _, fe := range configErr.Errors {
+		fields[fe.Field] = fe.Message
+	}
+	for _, field := range []string{"module", "observability", "database.port", "ports.api", "gitOptions.branch", "gitOptions.authorEmail", "project.license", "project.description", "project.homepage", "storage.s3"} {
+		if _, ok := fields[field]; !ok {
+			t.Fatalf("expected error for %s, got %v", field, err)
+		}
+	}
+	if !strings.Contains(fields["storage.s3"], "missing: region, bucket, accessKey, secretKey") {
+		t.Fatalf("expected missing s3 keys to be listed, got %q", fields["storage.s3"])
+	}
+}
@@ -0,0 +1,126 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictPolicy decides what happens to existing files that differ from the
+// generated ones when scaffolding into a non-empty directory.
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictBackup    ConflictPolicy = "backup"
+	ConflictPrompt    ConflictPolicy = "prompt"
+)
+
+var ConflictPolicies = []ConflictPolicy{ConflictSkip, ConflictOverwrite, ConflictBackup, ConflictPrompt}
+
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	for _, policy := range ConflictPolicies {
+		if string(policy) == value {
+			return policy, nil
+		}
+	}
+	names := make([]string, len(ConflictPolicies))
+	for i, policy := range ConflictPolicies {
+		names[i] = string(policy)
+	}
+	return "", fmt.Errorf("invalid conflict policy %q (use %s)", value, strings.Join(names, "|"))
+}
+
+// Resolution is what happens to a single conflicting file.
+type Resolution string
+
+const (
+	// ResolveKeep leaves the existing file alone.
+	ResolveKeep Resolution = "keep"
+	// ResolveReplace overwrites the existing file.
+	ResolveReplace Resolution = "replace"
+	// ResolveBackup renames the existing file to <file>.bak and writes the new one.
+	ResolveBackup Resolution = "backup"
+	// ResolveWriteNew keeps the existing file and writes <file>.new next to it.
+	ResolveWriteNew Resolution = "new"
+)
+
+// ConflictResolver returns the resolution for the slash-separated project
+// path of an existing file that differs from the generated one.
+type ConflictResolver func(path string) Resolution
+
+// Resolver returns the resolver for a policy that needs no questions. The
+// prompt policy has none; use ResolverFromChoices with the answers instead.
+func (p ConflictPolicy) Resolver() ConflictResolver {
+	resolution := map[ConflictPolicy]Resolution{
+		ConflictSkip:      ResolveKeep,
+		ConflictOverwrite: ResolveReplace,
+		ConflictBackup:    ResolveBackup,
+	}[p]
+	if resolution == "" {
+		return nil
+	}
+	return func(string) Resolution { return resolution }
+}
+
+// ResolverFromChoices resolves the paths in choices as chosen and keeps every
+// other existing file.
+func ResolverFromChoices(choices map[string]Resolution) ConflictResolver {
+	return func(path string) Resolution {
+		if resolution, ok := choices[path]; ok {
+			return resolution
+		}
+		return ResolveKeep
+	}
+}
+
+// Conflict is a planned file whose path already holds different content.
+type Conflict struct {
+	Path      string
+	Existing  []byte
+	Generated []byte
+}
+
+// Diff returns a unified diff from the existing file to the generated one.
+func (c Conflict) Diff() string {
+	var b strings.Builder
+	if err := writeUnifiedDiff(&b, "a/"+c.Path, "b/"+c.Path, c.Existing, c.Generated); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
+
+// FindConflicts lists the planned files that already exist in dir with
+// different content, in plan order.
+func FindConflicts(plan Plan, dir string) ([]Conflict, error) {
+	var conflicts []Conflict
+	for _, file := range plan.Files {
+		existing, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(existing, file.Content) {
+			conflicts = append(conflicts, Conflict{Path: file.Path, Existing: existing, Generated: file.Content})
+		}
+	}
+	return conflicts, nil
+}
+
+// freePath returns path, or path with a numeric suffix if it is taken.
+func freePath(path string) string {
+	candidate := path
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(candidate); errors.Is(err, fs.ErrNotExist) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s.%d", path, i)
+	}
+}
@@ -0,0 +1,82 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScaffoldFromFSConflictResolutions(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "demo")
+	writeTestFile(t, filepath.Join(dest, "README.md"), "my readme\n")
+	writeTestFile(t, filepath.Join(dest, "apps/api/main.go"), "package mine\n")
+	writeTestFile(t, filepath.Join(dest, "apps/api/.env.example"), "API_PORT=9090\n")
+	writeTestFile(t, filepath.Join(dest, "apps/api/.env.example.bak"), "older backup\n")
+
+	resolve := ResolverFromChoices(map[string]Resolution{
+		"README.md":             ResolveWriteNew,
+		"apps/api/.env.example": ResolveBackup,
+		"apps/api/main.go":      ResolveKeep,
+	})
+	if err := ScaffoldFromFS(stageConfig(t, dest), Options{Resolve: resolve}, stageSource(), map[string]map[string]string{}); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+
+	assertFileContent(t, filepath.Join(dest, "README.md"), "my readme\n")
+	assertFileContent(t, filepath.Join(dest, "README.md.new"), "# demo\n")
+	assertFileContent(t, filepath.Join(dest, "apps/api/main.go"), "package mine\n")
+	assertFileContent(t, filepath.Join(dest, "apps/api/.env.example"), "API_PORT=8080\n")
+	assertFileContent(t, filepath.Join(dest, "apps/api/.env.example.bak"), "older backup\n")
+	assertFileContent(t, filepath.Join(dest, "apps/api/.env.example.bak.1"), "API_PORT=9090\n")
+	// The generated .env did not exist yet, so it is written without asking.
+	assertFileContent(t, filepath.Join(dest, "apps/api/.env"), "API_PORT=8080\n")
+	assertDirEntries(t, parent, []string{"demo"})
+}
+
+func TestScaffoldFromFSRequiresResolverForNonEmptyDestination(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "demo")
+	writeTestFile(t, filepath.Join(dest, "README.md"), "my readme\n")
+
+	if err := ScaffoldFromFS(stageConfig(t, dest), Options{}, stageSource(), nil); err == nil {
+		t.Fatalf("expected non-empty destination to be refused")
+	}
+	assertDirEntries(t, dest, []string{"README.md"})
+}
+
+func TestFindConflicts(t *testing.T) {
+	dest := t.TempDir()
+	writeTestFile(t, filepath.Join(dest, "README.md"), "# demo\n")
+	writeTestFile(t, filepath.Join(dest, "apps/api/main.go"), "package mine\n")
+
+	cfg := stageConfig(t, dest)
+	plan, err := PlanProject(cfg, stageSource(), nil)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	conflicts, err := FindConflicts(plan, dest)
+	if err != nil {
+		t.Fatalf("find conflicts: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Path != "apps/api/main.go" {
+		t.Fatalf("expected only apps/api/main.go to conflict, got %+v", conflicts)
+	}
+	if diff := conflicts[0].Diff(); !strings.Contains(diff, "-package mine\n+package main\n") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	if policy, err := ParseConflictPolicy("backup"); err != nil || policy != ConflictBackup {
+		t.Fatalf("expected backup policy, got %q, %v", policy, err)
+	}
+	if _, err := ParseConflictPolicy("merge"); err == nil || !strings.Contains(err.Error(), "skip|overwrite|backup|prompt") {
+		t.Fatalf("expected invalid policy error, got %v", err)
+	}
+	if ConflictPrompt.Resolver() != nil {
+		t.Fatalf("prompt policy should have no static resolver")
+	}
+	if got := ConflictSkip.Resolver()("README.md"); got != ResolveKeep {
+		t.Fatalf("skip policy should keep files, got %s", got)
+	}
+}
@@ -2,19 +2,12 @@ package scaffold
 
 func DefaultConfig() ScaffoldConfiguration {
 	return ScaffoldConfiguration{
-		ProjectName:  "my-app",
-		Destination:  "",
-		ModulePath:   "github.com/yourorg/my-app",
-		IncludeWeb:   true,
-		DatabaseType: DatabasePostgres,
-		DBConnection: DBConnection{
-			Host:     "localhost",
-			Port:     "5432",
-			User:     "postgres",
-			Password: "postgres",
-			Name:     "app",
-			SSLMode:  "disable",
-		},
+		ProjectName:    "my-app",
+		Destination:    "",
+		ModulePath:     "github.com/yourorg/my-app",
+		IncludeWeb:     true,
+		DatabaseType:   DatabasePostgres,
+		DBConnection:   DefaultDBConnection(DatabasePostgres),
 		PackageManager: PackageBun,
 		IncludeDocker:  true,
 		InitGit:        true,
@@ -25,6 +18,48 @@ func DefaultConfig() ScaffoldConfiguration {
 			},
 		},
 		Observability: ObservabilityNone,
+		APIPort:       "8080",
+		WebPort:       "3000",
 		UseDefaults:   true,
+		Metadata:      ProjectMetadata{License: LicenseMIT},
+	}
+}
+
+// DefaultDBConnection returns the settings for a local database of type t,
+// matching the generated Docker Compose service.
+func DefaultDBConnection(t DatabaseType) DBConnection {
+	conn := DBConnection{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		Name:     "app",
+		SSLMode:  "disable",
+	}
+	if t == DatabaseMySQL {
+		conn.Port = "3306"
+		conn.User = "mysql"
+		conn.Password = "mysql"
+	}
+	return conn
+}
+
+// SetDatabaseType switches cfg to database t. Connection settings still at
+// the previous type's defaults move to the defaults of t.
+func SetDatabaseType(cfg *ScaffoldConfiguration, t DatabaseType) {
+	from, to := DefaultDBConnection(cfg.DatabaseType), DefaultDBConnection(t)
+	fields := []struct {
+		value    *string
+		from, to string
+	}{
+		{&cfg.DBConnection.Port, from.Port, to.Port},
+		{&cfg.DBConnection.User, from.User, to.User},
+		{&cfg.DBConnection.Password, from.Password, to.Password},
+	}
+	for _, f := range fields {
+		if *f.value == f.from {
+			*f.value = f.to
+		}
 	}
+	cfg.DatabaseType = t
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -15,7 +16,7 @@ func TestScaffoldProject_EmbeddedTemplateDefaultOutput(t *testing.T) {
 	cfg.Destination = t.TempDir()
 	cfg.InitGit = false
 
-	if err := ScaffoldProject(cfg, false); err != nil {
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
 		t.Fatalf("scaffold project: %v", err)
 	}
 
@@ -79,7 +80,7 @@ func TestScaffoldProject_EmbeddedTemplateWithoutWebOrDocker(t *testing.T) {
 	cfg.IncludeDocker = false
 	cfg.InitGit = false
 
-	if err := ScaffoldProject(cfg, false); err != nil {
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
 		t.Fatalf("scaffold project: %v", err)
 	}
 
@@ -114,7 +115,7 @@ func TestScaffoldProject_EmbeddedTemplateWithGrafanaObservability(t *testing.T)
 	cfg.InitGit = false
 	cfg.Observability = ObservabilityGrafanaOSS
 
-	if err := ScaffoldProject(cfg, false); err != nil {
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
 		t.Fatalf("scaffold project: %v", err)
 	}
 
@@ -127,11 +128,160 @@ func TestScaffoldProject_EmbeddedTemplateWithGrafanaObservability(t *testing.T)
 	}
 
 	apiEnv := mustReadFile(t, filepath.Join(cfg.Destination, "apps/api/.env"))
-	if !strings.Contains(apiEnv, `OTEL_EXPORTER_OTLP_ENDPOINT="http://localhost:4318"`) {
+	if !strings.Contains(apiEnv, `API_OBSERVABILITY.OTLP.ENDPOINT="http://localhost:4318"`) {
 		t.Fatalf("expected api .env to include OTEL configuration")
 	}
 }
 
+func TestScaffoldProject_EmbeddedTemplateWithOTLPObservability(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	cfg.Observability = ObservabilityOTLP
+
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	assertNotExists(t, filepath.Join(cfg.Destination, "ops/observability"))
+	assertExists(t, filepath.Join(cfg.Destination, "ops/otel-collector/config.yml"))
+	assertExists(t, filepath.Join(cfg.Destination, "apps/api/internal/infrastructure/observability/otel.go"))
+
+	compose := mustReadFile(t, filepath.Join(cfg.Destination, "docker-compose.yml"))
+	if !strings.Contains(compose, "otel-collector:") || strings.Contains(compose, "grafana") || strings.Contains(compose, "prometheus") {
+		t.Fatalf("expected only a bare collector in the compose file:\n%s", compose)
+	}
+
+	middleware := mustReadFile(t, filepath.Join(cfg.Destination, "apps/api/internal/interface/http/middleware/tracing.go"))
+	if !strings.Contains(middleware, "otel") {
+		t.Fatalf("expected tracing middleware to be instrumented")
+	}
+
+	obsConfig := mustReadFile(t, filepath.Join(cfg.Destination, "apps/api/internal/infrastructure/config/observability.go"))
+	for _, want := range []string{"Endpoint string", "Headers  map[string]string", "SamplingRatio *float64", "OTLPProtocolGRPC"} {
+		if !strings.Contains(obsConfig, want) {
+			t.Fatalf("expected observability config to contain %q:\n%s", want, obsConfig)
+		}
+	}
+}
+
+func TestScaffoldProject_EmbeddedTemplateWithPNPM(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	cfg.PackageManager = PackagePNPM
+
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	assertExists(t, filepath.Join(cfg.Destination, "pnpm-workspace.yaml"))
+	assertNotExists(t, filepath.Join(cfg.Destination, ".yarnrc.yml"))
+
+	bunRef := regexp.MustCompile(`(?i)\bbunx?\b`)
+	files := []string{
+		"package.json",
+		"packages/openapi/package.json",
+		"apps/web/Dockerfile",
+		"apps/api/Dockerfile",
+		".github/workflows/ci.yml",
+		"README.md",
+		"AGENTS.md",
+	}
+	for _, file := range files {
+		content := mustReadFile(t, filepath.Join(cfg.Destination, file))
+		if bunRef.MatchString(content) {
+			t.Fatalf("expected %s to have no bun references:\n%s", file, content)
+		}
+	}
+
+	pkg := mustReadFile(t, filepath.Join(cfg.Destination, "package.json"))
+	if !strings.Contains(pkg, `"packageManager": "pnpm@`) || strings.Contains(pkg, `"workspaces"`) {
+		t.Fatalf("expected package.json to pin pnpm and leave workspaces to pnpm-workspace.yaml:\n%s", pkg)
+	}
+	dockerfile := mustReadFile(t, filepath.Join(cfg.Destination, "apps/web/Dockerfile"))
+	if !strings.Contains(dockerfile, "COPY package.json pnpm-lock.yaml pnpm-workspace.yaml") {
+		t.Fatalf("expected web Dockerfile to copy the pnpm lock file:\n%s", dockerfile)
+	}
+	ci := mustReadFile(t, filepath.Join(cfg.Destination, ".github/workflows/ci.yml"))
+	if !strings.Contains(ci, "run: pnpm install --frozen-lockfile") || !strings.Contains(ci, "cache: pnpm") {
+		t.Fatalf("expected CI to install with pnpm:\n%s", ci)
+	}
+}
+
+func TestScaffoldProject_EmbeddedTemplateWithNPM(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	cfg.PackageManager = PackageNPM
+
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	assertNotExists(t, filepath.Join(cfg.Destination, "pnpm-workspace.yaml"))
+	webPkg := mustReadFile(t, filepath.Join(cfg.Destination, "apps/web/package.json"))
+	if strings.Contains(webPkg, "workspace:") || !strings.Contains(webPkg, `"@demo/zod": "*"`) {
+		t.Fatalf("expected npm workspace dependencies without the workspace: protocol:\n%s", webPkg)
+	}
+	dockerfile := mustReadFile(t, filepath.Join(cfg.Destination, "apps/web/Dockerfile"))
+	if !strings.Contains(dockerfile, "RUN npm ci --ignore-scripts") || !strings.Contains(dockerfile, "npm run build -- --filter=./apps/web") {
+		t.Fatalf("expected web Dockerfile to build with npm:\n%s", dockerfile)
+	}
+}
+
+func TestScaffoldProject_EmbeddedTemplateWithMySQL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	cfg.Observability = ObservabilityGrafanaOSS
+	SetDatabaseType(&cfg, DatabaseMySQL)
+
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	api := filepath.Join(cfg.Destination, "apps/api")
+	for path, want := range map[string]string{
+		"go.mod": "gorm.io/driver/mysql v1.5.7\n",
+		"internal/infrastructure/database/database.go":                    `gormmysql.New(`,
+		"internal/infrastructure/database/migrator.go":                    `migrate.NewWithInstance("iofs", sourceDriver, "mysql", driver)`,
+		"internal/infrastructure/database/migrations/000001_setup.up.sql": "active TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) VIRTUAL",
+		"internal/app/sqlerr/handler.go":                                  "*mysql.MySQLError",
+		"internal/testing/container.go":                                   `"mysql:8.4"`,
+		".env":                                                            `API_DATABASE.PORT="3306"`,
+	} {
+		if got := mustReadFile(t, filepath.Join(api, path)); !strings.Contains(got, want) {
+			t.Fatalf("expected %s to contain %q:\n%s", path, want, got)
+		}
+	}
+	for _, path := range []string{
+		"internal/infrastructure/database/database.go",
+		"internal/infrastructure/database/migrator.go",
+		"internal/app/sqlerr/handler.go",
+	} {
+		if got := mustReadFile(t, filepath.Join(api, path)); strings.Contains(got, "lib/pq") {
+			t.Fatalf("expected %s not to import lib/pq", path)
+		}
+	}
+
+	compose := mustReadFile(t, filepath.Join(cfg.Destination, "docker-compose.yml"))
+	if !strings.Contains(compose, "image: mysql:8.4") || !strings.Contains(compose, "mysqld-exporter:") || strings.Contains(compose, "postgres") {
+		t.Fatalf("expected a MySQL compose stack:\n%s", compose)
+	}
+	dashboards := filepath.Join(cfg.Destination, "ops/observability/grafana/grafana/dashboards")
+	assertExists(t, filepath.Join(dashboards, "mysql-overview.json"))
+	assertNotExists(t, filepath.Join(dashboards, "postgres-overview.json"))
+}
+
 func TestScaffoldProject_EmbeddedTemplateInitGit(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.ProjectName = "demo"
@@ -150,7 +300,7 @@ func TestScaffoldProject_EmbeddedTemplateInitGit(t *testing.T) {
 	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
 	t.Setenv("GIT_TERMINAL_PROMPT", "0")
 
-	if err := ScaffoldProject(cfg, false); err != nil {
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
 		t.Fatalf("scaffold project: %v", err)
 	}
 
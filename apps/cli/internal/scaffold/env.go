@@ -2,6 +2,8 @@ package scaffold
 
 import (
 	"bufio"
+	"bytes"
+	"sort"
 	"strings"
 )
 
@@ -25,10 +27,50 @@ func MergeEnvExample(input string, overrides map[string]string) string {
 		}
 		out = append(out, key+"="+val)
 	}
-	for key, val := range overrides {
+	// Append new keys in a stable order so regenerating produces the same file.
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
 		if !seen[key] {
-			out = append(out, key+"="+val)
+			keys = append(keys, key)
 		}
 	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		out = append(out, key+"="+overrides[key])
+	}
 	return strings.Join(out, "\n") + "\n"
 }
+
+// ParseEnv reads a dotenv file the way godotenv does for the cases the
+// template uses: optional `export`, quoted values and trailing comments. Keys
+// are returned in file order and upper-cased in values, because koanf matches
+// them case-insensitively.
+func ParseEnv(data []byte) ([]string, map[string]string) {
+	var keys []string
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+			if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+				value = value[1 : end+1]
+			}
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if _, seen := values[strings.ToUpper(key)]; !seen {
+			keys = append(keys, key)
+		}
+		values[strings.ToUpper(key)] = value
+	}
+	return keys, values
+}
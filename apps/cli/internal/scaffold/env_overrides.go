@@ -1,17 +1,45 @@
 package scaffold
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
+const (
+	apiEnvExample = "apps/api/.env.example"
+	webEnvExample = "apps/web/.env.example"
+	// composeEnvExample holds the variables docker-compose.yml interpolates.
+	composeEnvExample = ".env.example"
+)
+
+// envFile is the .env file generated from example.
+func envFile(example string) string {
+	return strings.TrimSuffix(example, ".example")
+}
+
+// EnvOverridesFromConfig returns the keys to change in each .env.example,
+// keyed by its path. Values shared between apps, such as the API URL the web
+// app calls or the database name Compose creates, are derived once from cfg so
+// every file agrees.
 func EnvOverridesFromConfig(cfg ScaffoldConfiguration) map[string]map[string]string {
-	key := "apps/api/.env.example"
 	overrides := map[string]map[string]string{}
+	dbPassword := cfg.DBConnection.Password
+	if cfg.Secrets.DBPassword != "" {
+		dbPassword = cfg.Secrets.DBPassword
+	}
+	apiURL, webURL := localURL(cfg.APIPort, "8080"), localURL(cfg.WebPort, "3000")
 
 	api := map[string]string{}
 	api["API_PRIMARY.APP_NAME"] = fmt.Sprintf("\"%s\"", cfg.ProjectName)
+	api["API_SERVER.PORT"] = fmt.Sprintf("\"%s\"", portOrDefault(cfg.APIPort, "8080"))
+	api["API_SERVER.CORS_ALLOWED_ORIGINS"] = fmt.Sprintf("\"%s\"", webURL)
+	api["API_AUTH.GOOGLE_REDIRECT_URL"] = fmt.Sprintf("\"%s/api/v1/auth/google/callback\"", apiURL)
+	api["API_AUTH.GOOGLE_SUCCESS_REDIRECT_URL"] = fmt.Sprintf("\"%s/auth/me\"", webURL)
+	api["API_AUTH.GOOGLE_FAILURE_REDIRECT_URL"] = fmt.Sprintf("\"%s/auth/login\"", webURL)
 	api["API_DATABASE.HOST"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.Host)
 	api["API_DATABASE.PORT"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.Port)
 	api["API_DATABASE.USER"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.User)
-	api["API_DATABASE.PASSWORD"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.Password)
+	api["API_DATABASE.PASSWORD"] = fmt.Sprintf("\"%s\"", dbPassword)
 	api["API_DATABASE.NAME"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.Name)
 	api["API_DATABASE.SSL_MODE"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.SSLMode)
 
@@ -26,7 +54,50 @@ func EnvOverridesFromConfig(cfg ScaffoldConfiguration) map[string]map[string]str
 		api["API_FILE_STORAGE.S3.ACCESS_KEY_ID"] = fmt.Sprintf("\"%s\"", cfg.Storage.S3.AccessKey)
 		api["API_FILE_STORAGE.S3.SECRET_ACCESS_KEY"] = fmt.Sprintf("\"%s\"", cfg.Storage.S3.SecretKey)
 	}
-	overrides[key] = api
+	if cfg.Secrets.AuthSecretKey != "" {
+		api["API_AUTH.SECRET_KEY"] = fmt.Sprintf("\"%s\"", cfg.Secrets.AuthSecretKey)
+	}
+	if cfg.Secrets.RedisPassword != "" {
+		api["API_CACHE.REDIS_PASSWORD"] = fmt.Sprintf("\"%s\"", cfg.Secrets.RedisPassword)
+	}
+	overrides[apiEnvExample] = api
+
+	if cfg.IncludeWeb {
+		web := map[string]string{}
+		web["VITE_API_URL"] = fmt.Sprintf("\"%s\"", apiURL)
+		web["WEB_PORT"] = fmt.Sprintf("\"%s\"", portOrDefault(cfg.WebPort, "3000"))
+		overrides[webEnvExample] = web
+	}
+
+	compose := map[string]string{}
+	compose["API_PORT"] = fmt.Sprintf("\"%s\"", portOrDefault(cfg.APIPort, "8080"))
+	if cfg.IncludeWeb {
+		compose["WEB_PORT"] = fmt.Sprintf("\"%s\"", portOrDefault(cfg.WebPort, "3000"))
+		compose["VITE_API_URL"] = fmt.Sprintf("\"%s\"", apiURL)
+	}
+	compose["DB_NAME"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.Name)
+	compose["DB_USER"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.User)
+	compose["DB_PORT"] = fmt.Sprintf("\"%s\"", cfg.DBConnection.Port)
+	compose["DB_PASSWORD"] = fmt.Sprintf("\"%s\"", dbPassword)
+	if cfg.Secrets.AuthSecretKey != "" {
+		compose["API_AUTH_SECRET_KEY"] = fmt.Sprintf("\"%s\"", cfg.Secrets.AuthSecretKey)
+	}
+	if cfg.Secrets.RedisPassword != "" {
+		compose["REDIS_PASSWORD"] = fmt.Sprintf("\"%s\"", cfg.Secrets.RedisPassword)
+	}
+	overrides[composeEnvExample] = compose
 
 	return overrides
 }
+
+// localURL is the URL of an app listening on port of this machine.
+func localURL(port, fallback string) string {
+	return "http://localhost:" + portOrDefault(port, fallback)
+}
+
+func portOrDefault(port, fallback string) string {
+	if port == "" {
+		return fallback
+	}
+	return port
+}
@@ -0,0 +1,75 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvOverridesDeriveCrossAppValues(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.APIPort = "9090"
+	cfg.WebPort = "5173"
+	cfg.DBConnection.Name = "shop"
+
+	overrides := EnvOverridesFromConfig(cfg)
+	for _, want := range []struct{ file, key, value string }{
+		{apiEnvExample, "API_SERVER.PORT", `"9090"`},
+		{apiEnvExample, "API_SERVER.CORS_ALLOWED_ORIGINS", `"http://localhost:5173"`},
+		{apiEnvExample, "API_AUTH.GOOGLE_REDIRECT_URL", `"http://localhost:9090/api/v1/auth/google/callback"`},
+		{apiEnvExample, "API_AUTH.GOOGLE_SUCCESS_REDIRECT_URL", `"http://localhost:5173/auth/me"`},
+		{apiEnvExample, "API_DATABASE.NAME", `"shop"`},
+		{webEnvExample, "VITE_API_URL", `"http://localhost:9090"`},
+		{webEnvExample, "WEB_PORT", `"5173"`},
+		{composeEnvExample, "API_PORT", `"9090"`},
+		{composeEnvExample, "WEB_PORT", `"5173"`},
+		{composeEnvExample, "VITE_API_URL", `"http://localhost:9090"`},
+		{composeEnvExample, "DB_NAME", `"shop"`},
+		{composeEnvExample, "DB_USER", `"postgres"`},
+	} {
+		if got := overrides[want.file][want.key]; got != want.value {
+			t.Fatalf("expected %s=%s in %s, got %q", want.key, want.value, want.file, got)
+		}
+	}
+
+	cfg.IncludeWeb = false
+	overrides = EnvOverridesFromConfig(cfg)
+	if _, ok := overrides[webEnvExample]; ok {
+		t.Fatalf("expected no web overrides without the web app")
+	}
+	if _, ok := overrides[composeEnvExample]["WEB_PORT"]; ok {
+		t.Fatalf("expected no WEB_PORT in the compose env without the web app")
+	}
+}
+
+func TestScaffoldProjectWritesEveryEnvFile(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	cfg.APIPort = "9090"
+	cfg.WebPort = "5173"
+
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	for _, want := range []struct{ path, line string }{
+		{"apps/web/.env", `VITE_API_URL="http://localhost:9090"`},
+		{"apps/api/.env", `API_SERVER.CORS_ALLOWED_ORIGINS="http://localhost:5173"`},
+		{".env", `API_PORT="9090"`},
+		{".env", `DB_NAME="app"`},
+	} {
+		if content := mustReadFile(t, filepath.Join(cfg.Destination, want.path)); !containsLine(content, want.line) {
+			t.Fatalf("expected %q in %s:\n%s", want.line, want.path, content)
+		}
+	}
+
+	compose := mustReadFile(t, filepath.Join(cfg.Destination, "docker-compose.yml"))
+	for _, want := range []string{`"${API_PORT:-8080}:${API_PORT:-8080}"`, `"${WEB_PORT:-3000}:3000"`, `POSTGRES_DB: "${DB_NAME:-demo}"`, `API_DATABASE.NAME: "${DB_NAME:-demo}"`} {
+		if !strings.Contains(compose, want) {
+			t.Fatalf("expected %s in docker-compose.yml:\n%s", want, compose)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateManifestPath is where a template declares its optional features.
+// The template's .gokickstart directory is never rendered into the project.
+const TemplateManifestPath = ManifestDir + "/template.yaml"
+
+const TemplateManifestVersion = 1
+
+// TemplateManifest describes the optional feature modules of a template:
+//
+//	version: 1
+//	features:
+//	  worker:
+//	    description: Background job worker
+//	    requires: [docker]
+//	    files: [apps/worker/**]
+//	    env:
+//	      apps/api/.env.example:
+//	        API_WORKER.CONCURRENCY: "10"
+//	    goMod:
+//	      apps/api/go.mod: [github.com/hibiken/asynq v0.25.1]
+//	    compose:
+//	      docker-compose.yml:
+//	        worker:
+//	          build: {context: ., dockerfile: apps/worker/Dockerfile}
+//
+// A feature with a `when` condition follows the configuration; any other
+// feature is only enabled when selected with --feature or required by an
+// enabled feature.
+type TemplateManifest struct {
+	Version  int                `yaml:"version"`
+	Features map[string]Feature `yaml:"features"`
+}
+
+type Feature struct {
+	Description string `yaml:"description,omitempty"`
+	// When is a condition in {{IF ...}} syntax.
+	When     string   `yaml:"when,omitempty"`
+	Requires []string `yaml:"requires,omitempty"`
+	// Files are globs of template paths (without .tmpl) that belong to the
+	// feature; ** matches any number of directories.
+	Files []string `yaml:"files,omitempty"`
+	// Env maps a .env.example file to the keys the feature adds to it.
+	Env map[string]map[string]string `yaml:"env,omitempty"`
+	// GoMod maps a go.mod file to "module version" requirements.
+	GoMod map[string][]string `yaml:"goMod,omitempty"`
+	// Compose maps a compose file to the services the feature adds to it.
+	Compose map[string]map[string]yaml.Node `yaml:"compose,omitempty"`
+
+	cond condition
+}
+
+var featureNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// LoadTemplateManifest reads the feature manifest of a template. A template
+// without one has no optional features.
+func LoadTemplateManifest(source fs.FS) (*TemplateManifest, error) {
+	data, err := fs.ReadFile(source, TemplateManifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &TemplateManifest{Version: TemplateManifestVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplateManifest(data)
+}
+
+// ParseTemplateManifest parses and validates a template manifest.
+func ParseTemplateManifest(data []byte) (*TemplateManifest, error) {
+	manifest := &TemplateManifest{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", TemplateManifestPath, err)
+	}
+	if manifest.Version != TemplateManifestVersion {
+		return nil, fmt.Errorf("%s: unsupported version %d (supported: %d)", TemplateManifestPath, manifest.Version, TemplateManifestVersion)
+	}
+	for name, feature := range manifest.Features {
+		if err := feature.validate(name, manifest.Features); err != nil {
+			return nil, fmt.Errorf("%s: feature %q: %w", TemplateManifestPath, name, err)
+		}
+		manifest.Features[name] = feature
+	}
+	return manifest, nil
+}
+
+func (f *Feature) validate(name string, all map[string]Feature) error {
+	if !featureNameRe.MatchString(name) {
+		return errors.New("names use lowercase letters, digits and dashes")
+	}
+	if f.When != "" {
+		cond, err := parseCondition(f.When)
+		if err != nil {
+			return fmt.Errorf("when: %w", err)
+		}
+		f.cond = cond
+	}
+	for _, dep := range f.Requires {
+		if _, ok := all[dep]; !ok {
+			return fmt.Errorf("requires unknown feature %q", dep)
+		}
+	}
+	for _, glob := range f.Files {
+		if _, err := path.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+			return fmt.Errorf("files: invalid glob %q", glob)
+		}
+	}
+	for file, requirements := range f.GoMod {
+		for _, req := range requirements {
+			if len(strings.Fields(req)) != 2 {
+				return fmt.Errorf("goMod: %s: %q is not \"module version\"", file, req)
+			}
+		}
+	}
+	return nil
+}
+
+// FeatureNames returns the declared features in alphabetical order.
+func (m *TemplateManifest) FeatureNames() []string {
+	names := make([]string, 0, len(m.Features))
+	for name := range m.Features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FeatureSet is the outcome of resolving a template's features for a
+// configuration.
+type FeatureSet struct {
+	manifest *TemplateManifest
+	enabled  map[string]bool
+}
+
+// EnableFeatures reads the template manifest of source and resolves it for cfg.
+func EnableFeatures(cfg ScaffoldConfiguration, source fs.FS) (FeatureSet, error) {
+	manifest, err := LoadTemplateManifest(source)
+	if err != nil {
+		return FeatureSet{}, err
+	}
+	return manifest.Enable(cfg)
+}
+
+// Enable works out which features are on: those whose condition holds, those
+// selected in cfg.Features, and everything they require.
+func (m *TemplateManifest) Enable(cfg ScaffoldConfiguration) (FeatureSet, error) {
+	set := FeatureSet{manifest: m, enabled: map[string]bool{}}
+	var queue []string
+	for _, name := range m.FeatureNames() {
+		if feature := m.Features[name]; feature.cond != nil && feature.cond.eval(cfg).(bool) {
+			queue = append(queue, name)
+		}
+	}
+	for _, name := range cfg.Features {
+		feature, ok := m.Features[name]
+		if !ok {
+			return set, fmt.Errorf("unknown feature %q (available: %s)", name, strings.Join(m.FeatureNames(), ", "))
+		}
+		if feature.When != "" {
+			return set, fmt.Errorf("feature %q follows the configuration (%s) and cannot be selected directly", name, feature.When)
+		}
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if set.enabled[name] {
+			continue
+		}
+		set.enabled[name] = true
+		for _, dep := range m.Features[name].Requires {
+			required := m.Features[dep]
+			if required.cond != nil && !required.cond.eval(cfg).(bool) {
+				return set, fmt.Errorf("feature %q requires %q, which is disabled by the configuration (%s)", name, dep, required.When)
+			}
+			queue = append(queue, dep)
+		}
+	}
+	return set, nil
+}
+
+// Enabled returns the enabled features in alphabetical order.
+func (s FeatureSet) Enabled() []string {
+	var names []string
+	for name := range s.enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Skip reports whether the template path belongs to a disabled feature. A
+// path matched by several features needs all of them enabled.
+func (s FeatureSet) Skip(templatePath string) bool {
+	if s.manifest == nil {
+		return false
+	}
+	target := stripTemplateSuffix(templatePath)
+	for name, feature := range s.manifest.Features {
+		if matchesAny(feature.Files, target) && !s.enabled[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// apply adds the env keys, go.mod requirements and compose services of the
+// enabled features to the rendered file at outPath. expand is applied to the
+// added text so it gets the same token replacement as the file.
+func (s FeatureSet) apply(outPath string, content []byte, expand func(string) string) ([]byte, error) {
+	for _, name := range s.Enabled() {
+		feature := s.manifest.Features[name]
+		if keys, ok := feature.Env[outPath]; ok {
+			expanded := make(map[string]string, len(keys))
+			for key, value := range keys {
+				expanded[key] = expand(value)
+			}
+			content = []byte(MergeEnvExample(string(content), expanded))
+		}
+		for _, req := range feature.GoMod[outPath] {
+			content = addGoRequirement(content, expand(req))
+		}
+		if services, ok := feature.Compose[outPath]; ok {
+			var err error
+			content, err = addComposeServices(content, services, expand)
+			if err != nil {
+				return nil, fmt.Errorf("feature %q: %s: %w", name, outPath, err)
+			}
+		}
+	}
+	return content, nil
+}
+
+// checkTargets reports files an enabled feature adds to that are not part of
+// the rendered project.
+func (s FeatureSet) checkTargets(rendered func(outPath string) bool) error {
+	for _, name := range s.Enabled() {
+		feature := s.manifest.Features[name]
+		var targets []string
+		for file := range feature.Env {
+			targets = append(targets, file)
+		}
+		for file := range feature.GoMod {
+			targets = append(targets, file)
+		}
+		for file := range feature.Compose {
+			targets = append(targets, file)
+		}
+		sort.Strings(targets)
+		for _, file := range targets {
+			if !rendered(file) {
+				return fmt.Errorf("feature %q adds to %s, which is not part of the project", name, file)
+			}
+		}
+	}
+	return nil
+}
+
+func matchesAny(globs []string, target string) bool {
+	for _, glob := range globs {
+		if matchGlob(strings.Split(glob, "/"), strings.Split(target, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches slash-separated segments; ** matches zero or more
+// segments, so apps/web/** also matches the apps/web directory itself.
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
+
+var requireBlockRe = regexp.MustCompile(`(?m)^require \($`)
+
+// addGoRequirement adds "module version" to the first require block of a
+// go.mod, unless the module is already required.
+func addGoRequirement(content []byte, requirement string) []byte {
+	fields := strings.Fields(requirement)
+	module := regexp.QuoteMeta(fields[0])
+	if regexp.MustCompile(`(?m)^(require\s+|\t)` + module + `\s`).Match(content) {
+		return content
+	}
+	line := fields[0] + " " + fields[1]
+	if loc := requireBlockRe.FindIndex(content); loc != nil {
+		if end := bytes.Index(content[loc[1]:], []byte("\n)")); end >= 0 {
+			at := loc[1] + end + 1
+			return append(content[:at:at], append([]byte("\t"+line+"\n"), content[at:]...)...)
+		}
+	}
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	return append(content, []byte("\nrequire "+line+"\n")...)
+}
+
+// addComposeServices inserts services at the end of the top-level services
+// mapping of a compose file, leaving the rest of the file as written.
+func addComposeServices(content []byte, services map[string]yaml.Node, expand func(string) string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parse compose file: %w", err)
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	insertAt, indent := len(lines), 2
+	var existing *yaml.Node
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		root := doc.Content[0]
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value != "services" {
+				continue
+			}
+			existing = root.Content[i+1]
+			if i+2 < len(root.Content) {
+				insertAt = root.Content[i+2].Line - 1
+			}
+			if len(existing.Content) > 0 {
+				indent = existing.Content[0].Column - 1
+			}
+		}
+	}
+	if existing != nil {
+		for i := 0; i < len(existing.Content); i += 2 {
+			if _, ok := services[existing.Content[i].Value]; ok {
+				return nil, fmt.Errorf("service %q already exists", existing.Content[i].Value)
+			}
+		}
+		// Keep the blank lines and comments that lead into the next key.
+		for insertAt > 0 && (strings.TrimSpace(lines[insertAt-1]) == "" || strings.HasPrefix(lines[insertAt-1], "#")) {
+			insertAt--
+		}
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var block strings.Builder
+	if existing == nil {
+		block.WriteString("\nservices:\n")
+	}
+	for _, name := range names {
+		node := services[name]
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(indent)
+		if err := enc.Encode(map[string]*yaml.Node{name: &node}); err != nil {
+			return nil, err
+		}
+		if existing != nil || block.Len() > len("\nservices:\n") {
+			block.WriteString("\n")
+		}
+		for _, line := range strings.SplitAfter(strings.TrimRight(buf.String(), "\n")+"\n", "\n") {
+			if line != "" {
+				block.WriteString(strings.Repeat(" ", indent) + line)
+			}
+		}
+	}
+
+	head := strings.Join(lines[:insertAt], "")
+	if head != "" && !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return []byte(head + expand(block.String()) + strings.Join(lines[insertAt:], "")), nil
+}
@@ -0,0 +1,191 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const webFeatureManifest = `version: 1
+features:
+  web:
+    when: web
+    files: [apps/web/**, packages/ui/**]
+`
+
+func TestEmbeddedTemplateFeatures(t *testing.T) {
+	source, err := EmbeddedTemplate()
+	if err != nil {
+		t.Fatalf("embedded template: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.IncludeWeb = false
+	cfg.IncludeDocker = false
+	cfg.Observability = ObservabilityNone
+
+	features, err := EnableFeatures(cfg, source)
+	if err != nil {
+		t.Fatalf("enable features: %v", err)
+	}
+	for _, path := range []string{
+		"apps/web",
+		"apps/web/src/main.tsx",
+		"packages/ui",
+		"packages/ui/src/index.ts",
+		"docker-compose.yml.tmpl",
+		"ops/observability/grafana/collector.yaml",
+		"apps/api/internal/infrastructure/observability/otel.go.tmpl",
+	} {
+		if !features.Skip(path) {
+			t.Fatalf("expected %s to be skipped", path)
+		}
+	}
+	if features.Skip("apps/api/main.go") {
+		t.Fatalf("did not expect api path to be skipped")
+	}
+
+	cfg.IncludeWeb = true
+	cfg.Observability = ObservabilityGrafanaOSS
+	features, err = EnableFeatures(cfg, source)
+	if err != nil {
+		t.Fatalf("enable features: %v", err)
+	}
+	if got := strings.Join(features.Enabled(), ","); got != "git,grafana-oss,opentelemetry,postgres-dashboard,web" {
+		t.Fatalf("unexpected enabled features: %s", got)
+	}
+	// A path owned by several features needs all of them.
+	if !features.Skip("ops/observability/grafana/grafana/dashboards/mysql-overview.json") {
+		t.Fatalf("expected the mysql dashboard to be skipped for postgres")
+	}
+}
+
+func TestOptInFeatureContributions(t *testing.T) {
+	source := fstest.MapFS{
+		TemplateManifestPath: {Data: []byte(`version: 1
+features:
+  docker:
+    when: docker
+    files: [docker-compose*]
+  queue:
+    requires: [docker]
+    env:
+      apps/api/.env.example:
+        API_QUEUE.URL: "redis://localhost:6379"
+    compose:
+      docker-compose.yml:
+        redis:
+          image: redis:7-alpine
+  worker:
+    requires: [queue]
+    files: [apps/worker/**]
+    env:
+      apps/api/.env.example:
+        API_WORKER.NAME: "{{PROJECT_NAME}}-worker"
+    goMod:
+      apps/api/go.mod: [github.com/hibiken/asynq v0.25.1]
+    compose:
+      docker-compose.yml:
+        worker:
+          image: "{{PROJECT_NAME}}-worker"
+          depends_on: [redis]
+`)},
+		"apps/api/.env.example":   {Data: []byte("API_PORT=8080\n")},
+		"apps/api/go.mod.tmpl":    {Data: []byte("module {{MODULE_PATH}}\n\ngo 1.24\n\nrequire (\n\tgithub.com/labstack/echo/v4 v4.13.3\n)\n")},
+		"apps/worker/main.go":     {Data: []byte("package main\n")},
+		"docker-compose.yml.tmpl": {Data: []byte("services:\n  api:\n    image: api\n\n# named volumes\nvolumes:\n  data:\n")},
+	}
+
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	plan, err := PlanProject(cfg, source, map[string]map[string]string{})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if planned(plan, "apps/worker/main.go") != nil {
+		t.Fatalf("did not expect the worker without --feature")
+	}
+	if got := string(planned(plan, "docker-compose.yml").Content); strings.Contains(got, "redis") {
+		t.Fatalf("did not expect queue services, got:\n%s", got)
+	}
+
+	cfg.Features = []string{"worker"}
+	plan, err = PlanProject(cfg, source, map[string]map[string]string{})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if planned(plan, "apps/worker/main.go") == nil {
+		t.Fatalf("expected worker files")
+	}
+	env := string(planned(plan, "apps/api/.env.example").Content)
+	if env != "API_PORT=8080\nAPI_QUEUE.URL=redis://localhost:6379\nAPI_WORKER.NAME=demo-worker\n" {
+		t.Fatalf("unexpected env example:\n%s", env)
+	}
+	goMod := string(planned(plan, "apps/api/go.mod").Content)
+	if !strings.Contains(goMod, "\tgithub.com/labstack/echo/v4 v4.13.3\n\tgithub.com/hibiken/asynq v0.25.1\n)\n") {
+		t.Fatalf("expected requirement in the require block:\n%s", goMod)
+	}
+	compose := string(planned(plan, "docker-compose.yml").Content)
+	wantCompose := "services:\n  api:\n    image: api\n\n  redis:\n    image: redis:7-alpine\n\n  worker:\n    image: \"demo-worker\"\n    depends_on: [redis]\n\n# named volumes\nvolumes:\n  data:\n"
+	if compose != wantCompose {
+		t.Fatalf("expected compose:\n%s\ngot:\n%s", wantCompose, compose)
+	}
+
+	cfg.IncludeDocker = false
+	if _, err := PlanProject(cfg, source, nil); err == nil || !strings.Contains(err.Error(), `"queue" requires "docker"`) {
+		t.Fatalf("expected disabled dependency error, got %v", err)
+	}
+	cfg.Features = []string{"cron"}
+	if _, err := PlanProject(cfg, source, nil); err == nil || !strings.Contains(err.Error(), `unknown feature "cron"`) {
+		t.Fatalf("expected unknown feature error, got %v", err)
+	}
+	cfg.Features = []string{"docker"}
+	if _, err := PlanProject(cfg, source, nil); err == nil || !strings.Contains(err.Error(), "cannot be selected directly") {
+		t.Fatalf("expected conditional feature error, got %v", err)
+	}
+}
+
+func TestParseTemplateManifestErrors(t *testing.T) {
+	cases := map[string]string{
+		"version: 2\n": "unsupported version 2",
+		"version: 1\nfeatures:\n  a:\n    when: webb\n":                `feature "a": when: unknown variable "webb"`,
+		"version: 1\nfeatures:\n  a:\n    requires: [b]\n":             `requires unknown feature "b"`,
+		"version: 1\nfeatures:\n  a:\n    goMod:\n      go.mod: [x]\n": `"x" is not "module version"`,
+		"version: 1\nfeatures:\n  a:\n    file: [x]\n":                 "field file not found",
+		"version: 1\nfeatures:\n  Bad_Name:\n    files: [x]\n":         "lowercase letters",
+	}
+	for input, want := range cases {
+		_, err := ParseTemplateManifest([]byte(input))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		glob, path string
+		want       bool
+	}{
+		{"apps/web/**", "apps/web", true},
+		{"apps/web/**", "apps/web/src/main.tsx", true},
+		{"apps/web/**", "apps/webhooks/main.go", false},
+		{"docker-compose*", "docker-compose.yml", true},
+		{"docker-compose*", "ops/docker-compose.yml", false},
+		{"**/*.sql", "apps/api/migrations/001.sql", true},
+	}
+	for _, c := range cases {
+		if got := matchesAny([]string{c.glob}, c.path); got != c.want {
+			t.Fatalf("%s ~ %s = %v, want %v", c.glob, c.path, got, c.want)
+		}
+	}
+}
+
+func planned(plan Plan, path string) *PlannedFile {
+	for i := range plan.Files {
+		if plan.Files[i].Path == path {
+			return &plan.Files[i]
+		}
+	}
+	return nil
+}
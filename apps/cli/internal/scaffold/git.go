@@ -1,24 +1,124 @@
 package scaffold
 
 import (
+	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 )
 
-func InitGitRepo(path string) error {
-	cmd := exec.Command("git", "init")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return err
+// DefaultCommitMessage is the message of the initial commit.
+const DefaultCommitMessage = "init: scaffolded a new project with go-kickstart CLI"
+
+// GitOptions controls the repository created when InitGit is set.
+type GitOptions struct {
+	// Branch is the initial branch; empty uses git's init.defaultBranch.
+	Branch string
+	// Remote is added as origin when set.
+	Remote string
+	// AuthorName and AuthorEmail override the git identity of the commit.
+	AuthorName    string
+	AuthorEmail   string
+	CommitMessage string
+	// NoCommit stages the generated files without committing them.
+	NoCommit bool
+}
+
+// NoGitIdentityWarning is reported when the initial commit is skipped because
+// git has no author name and email to commit with.
+const NoGitIdentityWarning = "no git identity is configured (user.name and user.email), so the files were staged without a commit"
+
+// initGitRepo and hasGitIdentity are replaced in tests.
+var (
+	initGitRepo    = InitGitRepo
+	hasGitIdentity = HasGitIdentity
+)
+
+// gitConfigValue is replaced in tests.
+var gitConfigValue = func(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
 	}
-	add := exec.Command("git", "add", ".")
-	add.Dir = path
-	if err := add.Run(); err != nil {
+	return strings.TrimSpace(string(out))
+}
+
+// InitGitRepo initializes a repository in path, stages every file and, unless
+// opts.NoCommit is set, creates the initial commit.
+func InitGitRepo(path string, opts GitOptions) error {
+	initArgs := []string{"init"}
+	if opts.Branch != "" {
+		initArgs = append(initArgs, "--initial-branch="+opts.Branch)
+	}
+	if err := runGit(path, initArgs...); err != nil {
 		return err
 	}
-	commit := exec.Command("git", "commit", "-m", "init: scaffolded a new project with go-kickstart CLI")
-	commit.Dir = path
-	if err := commit.Run(); err != nil {
+	if err := runGit(path, "add", "."); err != nil {
 		return err
 	}
+	if opts.Remote != "" {
+		// An existing destination may already be a repository with an origin.
+		if runGit(path, "remote", "get-url", "origin") == nil {
+			if err := runGit(path, "remote", "set-url", "origin", opts.Remote); err != nil {
+				return err
+			}
+		} else if err := runGit(path, "remote", "add", "origin", opts.Remote); err != nil {
+			return err
+		}
+	}
+	if opts.NoCommit {
+		return nil
+	}
+	var args []string
+	if opts.AuthorName != "" {
+		args = append(args, "-c", "user.name="+opts.AuthorName)
+	}
+	if opts.AuthorEmail != "" {
+		args = append(args, "-c", "user.email="+opts.AuthorEmail)
+	}
+	message := opts.CommitMessage
+	if message == "" {
+		message = DefaultCommitMessage
+	}
+	return runGit(path, append(args, "commit", "-m", message)...)
+}
+
+// HasGitIdentity reports whether a commit made with opts has an author name
+// and email, from opts, the git config or the GIT_AUTHOR_*/GIT_COMMITTER_*
+// environment. Without one, `git commit` fails.
+func HasGitIdentity(opts GitOptions) bool {
+	return hasIdentityPart(opts.AuthorName, "user.name", "NAME") &&
+		hasIdentityPart(opts.AuthorEmail, "user.email", "EMAIL")
+}
+
+func hasIdentityPart(override, key, env string) bool {
+	if override != "" || gitConfigValue(key) != "" {
+		return true
+	}
+	return os.Getenv("GIT_AUTHOR_"+env) != "" && os.Getenv("GIT_COMMITTER_"+env) != ""
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("git %s: %w: %s", gitSubcommand(args), err, msg)
+		}
+		return fmt.Errorf("git %s: %w", gitSubcommand(args), err)
+	}
 	return nil
 }
+
+// gitSubcommand names the command in args, skipping -c overrides.
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package scaffold
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestInitGitRepoAppliesOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "README.md"), "# demo\n")
+	opts := GitOptions{
+		Branch:        "trunk",
+		Remote:        "https://example.com/acme/demo.git",
+		AuthorName:    "Demo Bot",
+		AuthorEmail:   "bot@example.com",
+		CommitMessage: "chore: initial commit",
+	}
+	if err := InitGitRepo(dir, opts); err != nil {
+		t.Fatalf("init git repo: %v", err)
+	}
+	if got := gitOutput(t, dir, "branch", "--show-current"); got != "trunk" {
+		t.Fatalf("expected branch trunk, got %q", got)
+	}
+	if got := gitOutput(t, dir, "remote", "get-url", "origin"); got != opts.Remote {
+		t.Fatalf("expected origin %s, got %q", opts.Remote, got)
+	}
+	if got := gitOutput(t, dir, "log", "-1", "--format=%an <%ae> %s"); got != "Demo Bot <bot@example.com> chore: initial commit" {
+		t.Fatalf("unexpected commit: %q", got)
+	}
+}
+
+func TestInitGitRepoStagesWithoutCommitting(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "README.md"), "# demo\n")
+	if err := InitGitRepo(dir, GitOptions{NoCommit: true}); err != nil {
+		t.Fatalf("init git repo: %v", err)
+	}
+	if got := gitOutput(t, dir, "status", "--porcelain"); got != "A  README.md" {
+		t.Fatalf("expected README.md to be staged, got %q", got)
+	}
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "HEAD").Run(); err == nil {
+		t.Fatalf("expected no commit")
+	}
+}
+
+func TestHasGitIdentity(t *testing.T) {
+	config := map[string]string{}
+	original := gitConfigValue
+	gitConfigValue = func(key string) string { return config[key] }
+	t.Cleanup(func() { gitConfigValue = original })
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "")
+	}
+
+	if HasGitIdentity(GitOptions{}) {
+		t.Fatalf("expected no identity without config, env or overrides")
+	}
+	if !HasGitIdentity(GitOptions{AuthorName: "Demo", AuthorEmail: "demo@example.com"}) {
+		t.Fatalf("expected the overrides to be an identity")
+	}
+	config["user.name"] = "Demo"
+	if HasGitIdentity(GitOptions{}) {
+		t.Fatalf("expected an identity without an email to be incomplete")
+	}
+	t.Setenv("GIT_AUTHOR_EMAIL", "demo@example.com")
+	t.Setenv("GIT_COMMITTER_EMAIL", "demo@example.com")
+	if !HasGitIdentity(GitOptions{}) {
+		t.Fatalf("expected the config name and env email to be an identity")
+	}
+}
+
+func TestScaffoldFromFSStagesWithoutGitIdentity(t *testing.T) {
+	cfg := stageConfig(t, filepath.Join(t.TempDir(), "demo"))
+	cfg.InitGit = true
+	cfg.Git.Branch = "trunk"
+	var got GitOptions
+	initGitRepo = func(_ string, opts GitOptions) error {
+		got = opts
+		return nil
+	}
+	hasGitIdentity = func(GitOptions) bool { return false }
+	t.Cleanup(func() {
+		initGitRepo = InitGitRepo
+		hasGitIdentity = HasGitIdentity
+	})
+
+	var warnings []Event
+	opts := Options{Observe: func(event Event) {
+		if event.Kind == EventWarning {
+			warnings = append(warnings, event)
+		}
+	}}
+	if err := ScaffoldFromFS(cfg, opts, stageSource(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	if !got.NoCommit || got.Branch != "trunk" {
+		t.Fatalf("expected the files to be staged on trunk without a commit, got %+v", got)
+	}
+	if len(warnings) != 1 || warnings[0].Phase != PhaseGit || warnings[0].Message != NoGitIdentityWarning {
+		t.Fatalf("expected a git identity warning, got %+v", warnings)
+	}
+}
@@ -19,3 +19,22 @@ func TestDefaultSkipEnvExample(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultSkipNestedDirectories(t *testing.T) {
+	cases := []struct {
+		path string
+		skip bool
+	}{
+		{path: ".git", skip: true},
+		{path: "apps/web/node_modules", skip: true},
+		{path: "apps/web/node_modules/react/index.js", skip: true},
+		{path: "packages/ui/dist/index.js", skip: true},
+		{path: "apps/web/src/distance.ts", skip: false},
+	}
+
+	for _, c := range cases {
+		if got := DefaultSkip(c.path); got != c.skip {
+			t.Fatalf("DefaultSkip(%q) = %v, want %v", c.path, got, c.skip)
+		}
+	}
+}
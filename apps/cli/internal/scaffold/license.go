@@ -0,0 +1,79 @@
+package scaffold
+
+import (
+	"embed"
+	"strings"
+)
+
+var Licenses = []License{LicenseMIT, LicenseApache, LicenseBSD3, LicenseProprietary}
+
+//go:embed licenses/*.txt
+var licenseTexts embed.FS
+
+// licenseInfo describes how the generated project names a license.
+type licenseInfo struct {
+	// title is the display name used in the README.
+	title string
+	// packageLicense is the package.json "license" value.
+	packageLicense string
+	// spdx is the SPDX expression used in image labels.
+	spdx string
+}
+
+var licenseInfos = map[License]licenseInfo{
+	LicenseMIT:         {title: "MIT License", packageLicense: "MIT", spdx: "MIT"},
+	LicenseApache:      {title: "Apache License 2.0", packageLicense: "Apache-2.0", spdx: "Apache-2.0"},
+	LicenseBSD3:        {title: "BSD 3-Clause License", packageLicense: "BSD-3-Clause", spdx: "BSD-3-Clause"},
+	LicenseProprietary: {title: "Proprietary", packageLicense: "UNLICENSED", spdx: "LicenseRef-Proprietary"},
+}
+
+// ParseLicense returns the license called value, ignoring case; BSD-3 is
+// short for BSD-3-Clause. Unknown names are returned unchanged so
+// ValidateConfig reports them.
+func ParseLicense(value string) License {
+	if strings.EqualFold(value, "BSD-3") {
+		return LicenseBSD3
+	}
+	for _, license := range Licenses {
+		if strings.EqualFold(value, string(license)) {
+			return license
+		}
+	}
+	return License(value)
+}
+
+// metadataTokens returns the {{PROJECT_*}} metadata tokens and the LICENSE
+// text for cfg. Unset fields fall back to values derived from the project
+// name and module path, so every generated file has something to show.
+func metadataTokens(cfg ScaffoldConfiguration) map[string]string {
+	meta := cfg.Metadata
+	if meta.Description == "" {
+		meta.Description = cfg.ProjectName + ": a Go API with shared TypeScript packages"
+	}
+	if meta.Author == "" {
+		meta.Author = "The " + cfg.ProjectName + " authors"
+	}
+	if meta.Homepage == "" && cfg.ModulePath != "" {
+		meta.Homepage = "https://" + cfg.ModulePath
+	}
+	info := licenseInfos[meta.License]
+	return map[string]string{
+		"{{PROJECT_DESCRIPTION}}":   meta.Description,
+		"{{PROJECT_AUTHOR}}":        meta.Author,
+		"{{PROJECT_HOMEPAGE}}":      meta.Homepage,
+		"{{PROJECT_LICENSE}}":       info.packageLicense,
+		"{{PROJECT_LICENSE_TITLE}}": info.title,
+		"{{PROJECT_LICENSE_SPDX}}":  info.spdx,
+		"{{LICENSE_TEXT}}":          licenseText(meta.License, meta.Author),
+	}
+}
+
+// licenseText is the LICENSE file content of license with holder as the
+// copyright holder.
+func licenseText(license License, holder string) string {
+	data, err := licenseTexts.ReadFile("licenses/" + string(license) + ".txt")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ReplaceAll(string(data), "{{COPYRIGHT_HOLDER}}", holder), "\n")
+}
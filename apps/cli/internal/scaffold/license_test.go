@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestParseLicense(t *testing.T) {
+	for input, want := range map[string]License{
+		"MIT":          LicenseMIT,
+		"apache-2.0":   LicenseApache,
+		"BSD-3":        LicenseBSD3,
+		"bsd-3-clause": LicenseBSD3,
+		"Proprietary":  LicenseProprietary,
+		"GPL-3.0":      "GPL-3.0",
+	} {
+		if got := ParseLicense(input); got != want {
+			t.Fatalf("ParseLicense(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestMetadataTokens(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+
+	tokens := metadataTokens(cfg)
+	if tokens["{{PROJECT_AUTHOR}}"] != "The demo authors" || tokens["{{PROJECT_HOMEPAGE}}"] != "https://github.com/acme/demo" {
+		t.Fatalf("expected fallbacks from the name and module, got %v", tokens)
+	}
+	if !strings.HasPrefix(tokens["{{LICENSE_TEXT}}"], "MIT License\n\nCopyright (c) The demo authors\n") {
+		t.Fatalf("unexpected license text:\n%s", tokens["{{LICENSE_TEXT}}"])
+	}
+
+	cfg.Metadata = ProjectMetadata{Description: "Demo app", Author: "Acme Inc.", Homepage: "https://acme.dev", License: LicenseProprietary}
+	tokens = metadataTokens(cfg)
+	want := map[string]string{
+		"{{PROJECT_DESCRIPTION}}":   "Demo app",
+		"{{PROJECT_AUTHOR}}":        "Acme Inc.",
+		"{{PROJECT_HOMEPAGE}}":      "https://acme.dev",
+		"{{PROJECT_LICENSE}}":       "UNLICENSED",
+		"{{PROJECT_LICENSE_TITLE}}": "Proprietary",
+		"{{PROJECT_LICENSE_SPDX}}":  "LicenseRef-Proprietary",
+	}
+	for token, value := range want {
+		if tokens[token] != value {
+			t.Fatalf("expected %s to be %q, got %q", token, value, tokens[token])
+		}
+	}
+	if !strings.HasPrefix(tokens["{{LICENSE_TEXT}}"], "Copyright (c) Acme Inc.\nAll rights reserved.\n") {
+		t.Fatalf("unexpected license text:\n%s", tokens["{{LICENSE_TEXT}}"])
+	}
+}
+
+func TestEveryLicenseHasText(t *testing.T) {
+	for _, license := range Licenses {
+		if licenseText(license, "Acme") == "" {
+			t.Fatalf("no LICENSE text for %s", license)
+		}
+		if _, ok := licenseInfos[license]; !ok {
+			t.Fatalf("no license info for %s", license)
+		}
+	}
+}
+
+func TestEveryPackageJSONUsesTheProjectLicense(t *testing.T) {
+	source, err := EmbeddedTemplate()
+	if err != nil {
+		t.Fatalf("embedded template: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Metadata.Author = "Acme Inc."
+	cfg.Metadata.License = LicenseProprietary
+	files, err := RenderProject(cfg, source)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	checked := 0
+	for file, content := range files {
+		if path.Base(file) != "package.json" {
+			continue
+		}
+		var pkg struct {
+			Author  string `json:"author"`
+			License string `json:"license"`
+		}
+		if err := json.Unmarshal(content, &pkg); err != nil {
+			t.Fatalf("parse %s: %v", file, err)
+		}
+		if pkg.Author != "Acme Inc." || pkg.License != "UNLICENSED" {
+			t.Fatalf("expected %s to name the project author and license, got %+v", file, pkg)
+		}
+		checked++
+	}
+	if checked < 7 {
+		t.Fatalf("expected the root, app and package manifests, checked %d", checked)
+	}
+}
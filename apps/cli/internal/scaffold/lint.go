@@ -0,0 +1,718 @@
+package scaffold
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	gotoken "go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LintFinding is a problem LintTemplate found in a template.
+type LintFinding struct {
+	// File and Line point into the template. Problems on lines a feature
+	// added to a generated file point into the generated file instead.
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+	// Combination is the first configuration the problem showed up in, empty
+	// when it does not depend on the configuration. Combinations counts the
+	// configurations it showed up in.
+	Combination  string `json:"combination,omitempty"`
+	Combinations int    `json:"combinations,omitempty"`
+	Message      string `json:"message"`
+}
+
+func (f LintFinding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s", f.File, f.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+}
+
+// LintReport is the result of LintTemplate.
+type LintReport struct {
+	// Combinations is the number of configurations the template was rendered for.
+	Combinations int           `json:"combinations"`
+	Findings     []LintFinding `json:"findings"`
+}
+
+// LintTemplate renders source in memory for every combination of the
+// template condition variables and checks the result: condition markers must
+// be balanced, no {{...}} token may be left over, and every generated Go
+// package must parse and type-check against its generated go.mod. Imports of
+// modules required in go.mod are trusted without being checked.
+//
+// The returned error is about the lint itself (an unreadable template or
+// manifest, a missing Go toolchain); problems in the template are findings.
+func LintTemplate(source fs.FS) (LintReport, error) {
+	var report LintReport
+	manifest, err := LoadTemplateManifest(source)
+	if err != nil {
+		return report, err
+	}
+	sources, err := readLintSources(source)
+	if err != nil {
+		return report, err
+	}
+
+	l := newLinter()
+	for i, src := range sources {
+		if !strings.HasSuffix(src.path, ".tmpl") {
+			continue
+		}
+		tmpl, err := ParseTemplate(src.path, string(src.raw))
+		if err != nil {
+			sources[i].broken = true
+			l.addError("", src.path, err)
+		}
+		sources[i].tmpl = tmpl
+	}
+
+	// Many combinations enable the same features; skips maps the enabled
+	// features to the source files they leave out.
+	skips := map[string]map[string]bool{}
+	expanded := map[string][]byte{}
+	for _, combo := range lintCombinations() {
+		report.Combinations++
+		features, err := manifest.Enable(combo.cfg)
+		if err != nil {
+			l.add(combo.label, LintFinding{File: TemplateManifestPath, Message: err.Error()})
+			continue
+		}
+		enabled := strings.Join(features.Enabled(), ",")
+		if skips[enabled] == nil {
+			skips[enabled] = map[string]bool{}
+			for _, src := range sources {
+				skips[enabled][src.path] = src.skipped(features)
+			}
+		}
+
+		// The same steps as renderTransform, keeping track of template lines.
+		expand := tokenExpander(combo.cfg)
+		files := map[string][]byte{}
+		l.origins = map[string]lineOrigin{}
+		for _, src := range sources {
+			if src.broken || skips[enabled][src.path] {
+				continue
+			}
+			outPath := stripTemplateSuffix(src.path)
+			content, lines := src.raw, []int(nil)
+			if src.tmpl != nil {
+				var rendered string
+				rendered, lines = src.tmpl.renderLines(combo.cfg)
+				// Of the expanded tokens only the package manager ones vary.
+				key := string(combo.cfg.PackageManager) + "\x00" + rendered
+				if expanded[key] == nil {
+					expanded[key] = []byte(expand(rendered))
+				}
+				content = expanded[key]
+			}
+			applied, err := features.apply(outPath, content, expand)
+			if err != nil {
+				l.addError(combo.label, src.path, err)
+				continue
+			}
+			// Lines added by features have no template line.
+			if lines != nil && bytes.Equal(applied, content) {
+				l.origins[outPath] = lineOrigin{file: src.path, lines: lines}
+			}
+			files[outPath] = applied
+		}
+		if err := features.checkTargets(func(path string) bool { _, ok := files[path]; return ok }); err != nil {
+			l.add(combo.label, LintFinding{File: TemplateManifestPath, Message: err.Error()})
+		}
+		for _, path := range sortedKeys(files) {
+			l.checkTokens(combo.label, path, files[path])
+		}
+		l.checkGo(combo.label, files)
+		if l.fatal != nil {
+			return report, l.fatal
+		}
+	}
+
+	report.Findings = append([]LintFinding{}, l.findings...)
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+type lintDimension struct {
+	name   string
+	values []string
+	set    func(cfg *ScaffoldConfiguration, value string)
+}
+
+// lintMatrix lists the values of every template condition variable.
+var lintMatrix = []lintDimension{
+	{"web", []string{"true", "false"}, func(cfg *ScaffoldConfiguration, v string) { cfg.IncludeWeb = v == "true" }},
+	{"docker", []string{"true", "false"}, func(cfg *ScaffoldConfiguration, v string) { cfg.IncludeDocker = v == "true" }},
+	{"git", []string{"true", "false"}, func(cfg *ScaffoldConfiguration, v string) { cfg.InitGit = v == "true" }},
+	{"database", []string{string(DatabasePostgres), string(DatabaseMySQL)}, func(cfg *ScaffoldConfiguration, v string) {
+		cfg.DatabaseType = DatabaseType(v)
+		cfg.DBConnection = DefaultDBConnection(cfg.DatabaseType)
+	}},
+	{"packageManager", []string{string(PackageBun), string(PackageNPM), string(PackagePNPM), string(PackageYarn)}, func(cfg *ScaffoldConfiguration, v string) {
+		cfg.PackageManager = PackageManager(v)
+	}},
+	{"storage", []string{string(StorageLocal), string(StorageS3)}, func(cfg *ScaffoldConfiguration, v string) {
+		cfg.Storage = StorageConfig{Type: StorageType(v), Local: &LocalStorageConfig{Path: "storage"}}
+		if cfg.Storage.Type == StorageS3 {
+			cfg.Storage = StorageConfig{Type: StorageS3, S3: &S3Config{
+				Endpoint: "https://s3.example.com", Region: "us-east-1", Bucket: "my-app", AccessKey: "key", SecretKey: "secret",
+			}}
+		}
+	}},
+	{"observability", []string{string(ObservabilityNone), string(ObservabilityGrafanaOSS), string(ObservabilityOTLP)}, func(cfg *ScaffoldConfiguration, v string) {
+		cfg.Observability = ObservabilityProvider(v)
+	}},
+}
+
+type lintCombination struct {
+	cfg   ScaffoldConfiguration
+	label string
+}
+
+func lintCombinations() []lintCombination {
+	combos := []lintCombination{{cfg: DefaultConfig()}}
+	for _, dim := range lintMatrix {
+		var next []lintCombination
+		for _, combo := range combos {
+			for _, value := range dim.values {
+				cfg := combo.cfg
+				dim.set(&cfg, value)
+				label := strings.TrimSpace(combo.label + " " + dim.name + "=" + value)
+				next = append(next, lintCombination{cfg: cfg, label: label})
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+type lintSource struct {
+	path   string
+	raw    []byte
+	tmpl   *Template
+	broken bool
+}
+
+func readLintSources(source fs.FS) ([]lintSource, error) {
+	var sources []lintSource
+	err := fs.WalkDir(source, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+		if DefaultSkip(path) || isTemplateMeta(path) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		raw, err := fs.ReadFile(source, path)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, lintSource{path: path, raw: raw})
+		return nil
+	})
+	return sources, err
+}
+
+// skipped reports whether the file or one of its directories belongs to a
+// disabled feature.
+func (s lintSource) skipped(features FeatureSet) bool {
+	for p := s.path; p != "."; p = path.Dir(p) {
+		if features.Skip(p) {
+			return true
+		}
+	}
+	return false
+}
+
+type linter struct {
+	fset     *gotoken.FileSet
+	std      types.Importer
+	findings []LintFinding
+	// index maps a finding to its position in findings; seen remembers the
+	// last combination it was counted for.
+	index map[string]int
+	seen  map[string]string
+	// cache holds the findings of checks already run on identical input.
+	cache map[[sha256.Size]byte][]LintFinding
+	// origins maps the rendered *.tmpl files of the current combination
+	// back to their template lines.
+	origins map[string]lineOrigin
+	fatal   error
+}
+
+type lineOrigin struct {
+	file  string
+	lines []int
+}
+
+func newLinter() *linter {
+	fset := gotoken.NewFileSet()
+	return &linter{
+		fset:  fset,
+		std:   importer.ForCompiler(fset, "gc", nil),
+		index: map[string]int{},
+		seen:  map[string]string{},
+		cache: map[[sha256.Size]byte][]LintFinding{},
+	}
+}
+
+func (l *linter) add(combo string, f LintFinding) {
+	if origin, ok := l.origins[f.File]; ok && f.Line > 0 && f.Line <= len(origin.lines) {
+		f.File, f.Line = origin.file, origin.lines[f.Line-1]
+	}
+	key := fmt.Sprintf("%s:%d:%s", f.File, f.Line, f.Message)
+	i, ok := l.index[key]
+	if !ok {
+		f.Combination = combo
+		l.index[key] = len(l.findings)
+		l.findings = append(l.findings, f)
+		i = len(l.findings) - 1
+	} else if l.seen[key] == combo {
+		return
+	}
+	l.seen[key] = combo
+	if combo != "" {
+		l.findings[i].Combinations++
+	}
+}
+
+func (l *linter) addError(combo, file string, err error) {
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
+		l.add(combo, LintFinding{File: templateErr.File, Line: templateErr.Line, Message: templateErr.Message})
+		return
+	}
+	l.add(combo, LintFinding{File: file, Message: err.Error()})
+}
+
+// cached runs check unless it already ran for the same key and records its
+// findings for combo.
+func (l *linter) cached(combo string, key [sha256.Size]byte, check func() []LintFinding) {
+	findings, ok := l.cache[key]
+	if !ok {
+		findings = check()
+		l.cache[key] = findings
+	}
+	for _, f := range findings {
+		l.add(combo, f)
+	}
+}
+
+// leftoverTokenRe matches replacement tokens and condition markers. Lowercase
+// and spaced forms such as JSX objects or GitHub Actions expressions are left
+// alone.
+var leftoverTokenRe = regexp.MustCompile(`\{\{(?:[A-Z][A-Z0-9_]*|IF\s[^}]*)\}\}`)
+
+func (l *linter) checkTokens(combo, file string, content []byte) {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return
+	}
+	l.cached(combo, hashOf([]byte("tokens"), []byte(file), content), func() []LintFinding {
+		var findings []LintFinding
+		text := string(content)
+		for _, loc := range leftoverTokenRe.FindAllStringIndex(text, -1) {
+			tok := text[loc[0]:loc[1]]
+			msg := "leftover template token " + tok
+			if tagRe.MatchString(tok) {
+				msg = "leftover condition marker " + tok
+			}
+			findings = append(findings, LintFinding{File: file, Line: lineAt(text, loc[0]), Message: msg})
+		}
+		return findings
+	})
+}
+
+func hashOf(parts ...[]byte) [sha256.Size]byte {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(binary.AppendUvarint(nil, uint64(len(part))))
+		h.Write(part)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// CheckGo parses and type-checks the Go packages in files, keyed by slash
+// separated path, the way LintTemplate checks a rendered combination. It lets
+// code that edits a generated project check the result.
+func CheckGo(files map[string][]byte) ([]LintFinding, error) {
+	l := newLinter()
+	l.checkGo("", files)
+	return l.findings, l.fatal
+}
+
+// checkGo type-checks the Go packages of every generated go.mod. Go files
+// outside of a module are only parsed.
+func (l *linter) checkGo(combo string, files map[string][]byte) {
+	var modDirs []string
+	for file := range files {
+		if path.Base(file) == "go.mod" {
+			modDirs = append(modDirs, path.Dir(file))
+		}
+	}
+	// Longest first, so a file belongs to its innermost module.
+	sort.Slice(modDirs, func(i, j int) bool { return len(modDirs[i]) > len(modDirs[j]) })
+
+	byModule := map[string][]string{}
+	var loose []string
+	for _, file := range sortedKeys(files) {
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		owner, ok := "", false
+		for _, dir := range modDirs {
+			if dir == "." || strings.HasPrefix(file, dir+"/") {
+				owner, ok = dir, true
+				break
+			}
+		}
+		if !ok {
+			loose = append(loose, file)
+			continue
+		}
+		byModule[owner] = append(byModule[owner], file)
+	}
+
+	for _, file := range loose {
+		l.cached(combo, hashOf([]byte("parse"), []byte(file), files[file]), func() []LintFinding {
+			_, err := parser.ParseFile(gotoken.NewFileSet(), file, files[file], parser.SkipObjectResolution)
+			return syntaxFindings(file, err)
+		})
+	}
+	for _, dir := range sortedKeys(byModule) {
+		goFiles := byModule[dir]
+		modFile := path.Join(dir, "go.mod")
+		parts := [][]byte{[]byte("module"), []byte(modFile), files[modFile]}
+		for _, file := range goFiles {
+			parts = append(parts, []byte(file), files[file])
+		}
+		l.cached(combo, hashOf(parts...), func() []LintFinding {
+			return l.checkModule(dir, files, goFiles)
+		})
+	}
+}
+
+// errTrusted marks imports from required modules, which are not checked. The
+// type checker treats the packages as fake and stays quiet about their use.
+var errTrusted = errors.New("provided by a required module")
+
+type goModule struct {
+	dir      string
+	file     string
+	path     string
+	requires []string
+}
+
+type goPackage struct {
+	importPath string
+	files      []*ast.File
+	tests      []*ast.File
+	xtests     []*ast.File
+}
+
+type moduleImporter struct {
+	l        *linter
+	mod      goModule
+	pkgs     map[string]*goPackage
+	checked  map[string]*types.Package
+	checking map[string]bool
+	report   func(error)
+}
+
+func (l *linter) checkModule(dir string, files map[string][]byte, goFiles []string) []LintFinding {
+	var findings []LintFinding
+	mod, err := parseGoMod(path.Join(dir, "go.mod"), files[path.Join(dir, "go.mod")])
+	if err != nil {
+		var templateErr *TemplateError
+		if errors.As(err, &templateErr) {
+			return []LintFinding{{File: templateErr.File, Line: templateErr.Line, Message: templateErr.Message}}
+		}
+		return []LintFinding{{File: path.Join(dir, "go.mod"), Message: err.Error()}}
+	}
+	mod.dir = dir
+
+	ctxt := build.Default
+	ctxt.CgoEnabled = false
+	ctxt.JoinPath = path.Join
+	ctxt.OpenFile = func(name string) (io.ReadCloser, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	pkgs := map[string]*goPackage{}
+	for _, file := range goFiles {
+		if ok, err := ctxt.MatchFile(path.Dir(file), path.Base(file)); err != nil || !ok {
+			continue
+		}
+		parsed, err := parser.ParseFile(l.fset, file, files[file], parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			findings = append(findings, syntaxFindings(file, err)...)
+			continue
+		}
+		pkgDir := path.Dir(file)
+		pkg := pkgs[pkgDir]
+		if pkg == nil {
+			pkg = &goPackage{importPath: mod.importPath(pkgDir)}
+			pkgs[pkgDir] = pkg
+		}
+		switch {
+		case !strings.HasSuffix(file, "_test.go"):
+			pkg.files = append(pkg.files, parsed)
+		case strings.HasSuffix(parsed.Name.Name, "_test"):
+			pkg.xtests = append(pkg.xtests, parsed)
+		default:
+			pkg.tests = append(pkg.tests, parsed)
+		}
+	}
+
+	seen := map[string]bool{}
+	im := &moduleImporter{l: l, mod: mod, pkgs: pkgs, checked: map[string]*types.Package{}, checking: map[string]bool{}}
+	im.report = func(err error) {
+		typeErr, ok := err.(types.Error)
+		if !ok || strings.Contains(typeErr.Msg, errTrusted.Error()) {
+			return
+		}
+		pos := typeErr.Fset.Position(typeErr.Pos)
+		f := LintFinding{File: pos.Filename, Line: pos.Line, Message: typeErr.Msg}
+		if key := f.String(); !seen[key] {
+			seen[key] = true
+			findings = append(findings, f)
+		}
+	}
+	for _, pkgDir := range sortedKeys(pkgs) {
+		pkg := pkgs[pkgDir]
+		if len(pkg.files) > 0 {
+			im.Import(pkg.importPath)
+		}
+		if len(pkg.tests) > 0 || len(pkg.xtests) > 0 {
+			tested := im.check(pkg.importPath, append(append([]*ast.File{}, pkg.files...), pkg.tests...))
+			if len(pkg.xtests) > 0 {
+				// External tests see the package together with its test files.
+				saved := im.checked[pkg.importPath]
+				im.checked[pkg.importPath] = tested
+				im.check(pkg.importPath+"_test", pkg.xtests)
+				im.checked[pkg.importPath] = saved
+			}
+		}
+	}
+	return findings
+}
+
+func (im *moduleImporter) Import(importPath string) (*types.Package, error) {
+	switch {
+	case isStdImport(importPath):
+		pkg, err := im.l.std.Import(importPath)
+		if err != nil && im.l.fatal == nil {
+			im.l.fatal = fmt.Errorf("type-checking needs the Go toolchain: %w", err)
+		}
+		return pkg, err
+	case importPath == im.mod.path || strings.HasPrefix(importPath, im.mod.path+"/"):
+		if pkg, ok := im.checked[importPath]; ok {
+			return pkg, nil
+		}
+		if im.checking[importPath] {
+			return nil, fmt.Errorf("import cycle")
+		}
+		dir := path.Join(im.mod.dir, strings.TrimPrefix(strings.TrimPrefix(importPath, im.mod.path), "/"))
+		pkg := im.pkgs[dir]
+		if pkg == nil || len(pkg.files) == 0 {
+			return nil, fmt.Errorf("no Go files are generated in %s", dir)
+		}
+		im.checking[importPath] = true
+		checked := im.check(importPath, pkg.files)
+		delete(im.checking, importPath)
+		im.checked[importPath] = checked
+		return checked, nil
+	case im.mod.provides(importPath):
+		return types.NewPackage(importPath, guessPackageName(importPath)), errTrusted
+	}
+	return nil, fmt.Errorf("no module in %s provides it", im.mod.file)
+}
+
+func (im *moduleImporter) check(importPath string, files []*ast.File) *types.Package {
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	selected := map[gotoken.Pos]ast.Expr{}
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				selected[sel.Sel.Pos()] = sel.X
+			}
+			return true
+		})
+	}
+	conf := types.Config{Importer: im, Error: func(err error) {
+		// Fields and methods promoted from an unchecked package are unknown.
+		if typeErr, ok := err.(types.Error); ok {
+			if x, ok := selected[typeErr.Pos]; ok && embedsUnchecked(info.TypeOf(x), map[types.Type]bool{}) {
+				return
+			}
+		}
+		im.report(err)
+	}}
+	pkg, _ := conf.Check(importPath, im.l.fset, files, info)
+	return pkg
+}
+
+// embedsUnchecked reports whether t embeds a type from an unchecked package,
+// which the type checker sees as invalid.
+func embedsUnchecked(t types.Type, seen map[types.Type]bool) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if t == nil || seen[t] {
+		return false
+	}
+	seen[t] = true
+	var embedded []types.Type
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if u.Field(i).Embedded() {
+				embedded = append(embedded, u.Field(i).Type())
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < u.NumEmbeddeds(); i++ {
+			embedded = append(embedded, u.EmbeddedType(i))
+		}
+	}
+	for _, e := range embedded {
+		if ptr, ok := e.(*types.Pointer); ok {
+			e = ptr.Elem()
+		}
+		if basic, ok := e.(*types.Basic); (ok && basic.Kind() == types.Invalid) || embedsUnchecked(e, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func syntaxFindings(file string, err error) []LintFinding {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return []LintFinding{{File: file, Message: err.Error()}}
+	}
+	findings := make([]LintFinding, 0, len(list))
+	for _, e := range list {
+		findings = append(findings, LintFinding{File: e.Pos.Filename, Line: e.Pos.Line, Message: e.Msg})
+	}
+	return findings
+}
+
+// parseGoMod reads the module path and the required modules of a go.mod.
+func parseGoMod(file string, content []byte) (goModule, error) {
+	mod := goModule{file: file}
+	inRequire := false
+	for i, line := range strings.Split(string(content), "\n") {
+		if j := strings.Index(line, "//"); j >= 0 {
+			line = line[:j]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire:
+			mod.requires = append(mod.requires, fields[0])
+		case fields[0] == "module" && len(fields) == 2:
+			mod.path = strings.Trim(fields[1], `"`)
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) == 3:
+			mod.requires = append(mod.requires, fields[1])
+		case fields[0] == "module" || fields[0] == "require":
+			return mod, &TemplateError{file, i + 1, "malformed " + fields[0] + " directive"}
+		}
+	}
+	if mod.path == "" {
+		return mod, &TemplateError{file, 1, "missing module directive"}
+	}
+	return mod, nil
+}
+
+func (m goModule) importPath(dir string) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(dir, m.dir), "/")
+	if m.dir == "." {
+		rel = dir
+	}
+	if rel == "" || rel == "." {
+		return m.path
+	}
+	return m.path + "/" + rel
+}
+
+func (m goModule) provides(importPath string) bool {
+	for _, req := range m.requires {
+		if importPath == req || strings.HasPrefix(importPath, req+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isStdImport uses the go command's rule: standard library paths have no dot
+// in their first element.
+func isStdImport(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+var majorVersionRe = regexp.MustCompile(`^v[0-9]+$`)
+
+// guessPackageName guesses the name of an unchecked package from its import
+// path, the way goimports does: github.com/go-redis/redis/v9 is redis and
+// gopkg.in/gomail.v2 is gomail.
+func guessPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if majorVersionRe.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
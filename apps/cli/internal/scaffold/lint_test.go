@@ -0,0 +1,108 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLintTemplateFindings(t *testing.T) {
+	source := fstest.MapFS{
+		"go.mod.tmpl": {Data: []byte("module {{MODULE_PATH}}\n\ngo 1.24\n\nrequire example.com/lib/v2 v2.0.0\n")},
+		"cmd/api/main.go.tmpl": {Data: []byte(`package main
+
+import (
+	"fmt"
+{{IF web}}
+	"github.com/acme/missing"
+{{END}}
+
+	"example.com/lib/v2"
+	"{{MODULE_PATH}}/internal/greet"
+)
+
+func main() {
+	fmt.Println(lib.Name, greet.Hello())
+{{IF database == "mysql"}}
+	fmt.Println(driverName)
+{{END}}
+}
+`)},
+		"internal/greet/greet.go": {Data: []byte("package greet\n\nfunc Hello() string { return \"hello\" }\n")},
+		"README.md.tmpl":          {Data: []byte("# {{PROJECT_NAME}}\n\nRun {{PROJECT_NAM}}.\n")},
+		"notes.txt.tmpl":          {Data: []byte("{{IF web}}\nweb\n")},
+	}
+
+	report, err := LintTemplate(source)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if report.Combinations != 384 {
+		t.Fatalf("expected 384 combinations, got %d", report.Combinations)
+	}
+
+	want := []struct {
+		location, message string
+		combinations      int
+		combination       string
+	}{
+		{"README.md.tmpl:3", "leftover template token {{PROJECT_NAM}}", 384, "web=true"},
+		{"cmd/api/main.go.tmpl:6", "could not import github.com/acme/missing (no module in go.mod provides it)", 192, "web=true"},
+		{"cmd/api/main.go.tmpl:16", "undefined: driverName", 192, "database=mysql"},
+		{"notes.txt.tmpl:1", "{{IF}} is never closed with {{END}}", 0, ""},
+	}
+	if len(report.Findings) != len(want) {
+		t.Fatalf("expected %d findings, got %v", len(want), report.Findings)
+	}
+	for i, w := range want {
+		got := report.Findings[i]
+		if !strings.HasPrefix(got.String(), w.location+": ") || !strings.Contains(got.Message, w.message) {
+			t.Fatalf("finding %d: expected %s: %s, got %s", i, w.location, w.message, got)
+		}
+		if got.Combinations != w.combinations || !strings.Contains(got.Combination, w.combination) {
+			t.Fatalf("finding %d: expected %d combinations like %q, got %d %q", i, w.combinations, w.combination, got.Combinations, got.Combination)
+		}
+	}
+}
+
+func TestLintMatrixCoversConditionVariables(t *testing.T) {
+	dims := map[string]bool{}
+	for _, dim := range lintMatrix {
+		dims[dim.name] = true
+	}
+	for _, name := range ConditionVariables() {
+		if !dims[name] {
+			t.Fatalf("condition variable %s is missing from the lint matrix", name)
+		}
+	}
+}
+
+func TestLintEmbeddedTemplate(t *testing.T) {
+	source, err := EmbeddedTemplate()
+	if err != nil {
+		t.Fatalf("embedded template: %v", err)
+	}
+	report, err := LintTemplate(source)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	for _, finding := range report.Findings {
+		t.Errorf("%s (%s)", finding, finding.Combination)
+	}
+}
+
+func TestGuessPackageName(t *testing.T) {
+	cases := map[string]string{
+		"github.com/gofiber/fiber/v2":                      "fiber",
+		"github.com/redis/go-redis/v9":                     "redis",
+		"gopkg.in/gomail.v2":                               "gomail",
+		"github.com/testcontainers/testcontainers-go":      "testcontainers",
+		"go.opentelemetry.io/otel/sdk/resource":            "resource",
+		"github.com/golang-migrate/migrate/v4/source/iofs": "iofs",
+	}
+	for path, want := range cases {
+		if got := guessPackageName(path); got != want {
+			t.Fatalf("guessPackageName(%s) = %s, want %s", path, got, want)
+		}
+	}
+}
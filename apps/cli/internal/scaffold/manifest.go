@@ -0,0 +1,168 @@
+package scaffold
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/version"
+)
+
+const (
+	ManifestDir           = ".gokickstart"
+	ManifestFile          = "manifest.json"
+	ManifestSchemaVersion = 1
+
+	redacted = "<redacted>"
+)
+
+// Manifest records how a project was generated so it can be audited and
+// upgraded later. It is written to .gokickstart/manifest.json.
+type Manifest struct {
+	SchemaVersion    int               `json:"schemaVersion"`
+	GeneratorVersion string            `json:"generatorVersion"`
+	GeneratedAt      time.Time         `json:"generatedAt"`
+	UpgradedAt       *time.Time        `json:"upgradedAt,omitempty"`
+	TemplateHash     string            `json:"templateHash"`
+	Config           ConfigFile        `json:"config"`
+	Files            map[string]string `json:"files"`
+}
+
+func ManifestPath(root string) string {
+	return filepath.Join(root, ManifestDir, ManifestFile)
+}
+
+func NewManifest(cfg ScaffoldConfiguration, templateHash string, files map[string]string) Manifest {
+	return Manifest{
+		SchemaVersion:    ManifestSchemaVersion,
+		GeneratorVersion: version.Version,
+		GeneratedAt:      time.Now().UTC().Truncate(time.Second),
+		TemplateHash:     templateHash,
+		Config:           RedactConfigFile(ConfigFileFrom(cfg)),
+		Files:            files,
+	}
+}
+
+func WriteManifest(root string, manifest Manifest) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	path := ManifestPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func ReadManifest(root string) (Manifest, error) {
+	var manifest Manifest
+	data, err := os.ReadFile(ManifestPath(root))
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("parse %s: %w", ManifestPath(root), err)
+	}
+	if manifest.SchemaVersion != ManifestSchemaVersion {
+		return manifest, fmt.Errorf("unsupported manifest schema version %d", manifest.SchemaVersion)
+	}
+	return manifest, nil
+}
+
+// FindManifestRoot walks up from start until it finds a generated project.
+func FindManifestRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(ManifestPath(dir)); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no .gokickstart/manifest.json found (was this project generated by gokickstart?)")
+		}
+		dir = parent
+	}
+}
+
+// RedactConfigFile blanks out credentials so the manifest can be committed.
+func RedactConfigFile(file ConfigFile) ConfigFile {
+	return replaceSecrets(file, redacted, redacted, redacted)
+}
+
+// HashTemplate returns a content hash of every file in the template source,
+// identifying the template revision independently of the CLI version.
+func HashTemplate(source fs.FS) (string, error) {
+	h := sha256.New()
+	err := fs.WalkDir(source, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != "." && DefaultSkip(path) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := fs.ReadFile(source, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+type FileStatus string
+
+const (
+	FileUnchanged FileStatus = "unchanged"
+	FileModified  FileStatus = "modified"
+	FileMissing   FileStatus = "missing"
+)
+
+// VerifyFiles compares the checksums recorded in the manifest with the files
+// currently on disk under root.
+func (m Manifest) VerifyFiles(root string) (map[string]FileStatus, error) {
+	statuses := make(map[string]FileStatus, len(m.Files))
+	for path, sum := range m.Files {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			statuses[path] = FileMissing
+		case err != nil:
+			return nil, err
+		case Checksum(data) != sum:
+			statuses[path] = FileModified
+		default:
+			statuses[path] = FileUnchanged
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,121 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/version"
+)
+
+func TestScaffoldWritesManifest(t *testing.T) {
+	source := fstest.MapFS{
+		"README.md.tmpl":          {Data: []byte("# {{PROJECT_NAME}}\n")},
+		"apps/api/main.go":        {Data: []byte("package main\n")},
+		"apps/api/.env.example":   {Data: []byte("API_KEY=\n")},
+		"apps/api/.env.tmpl":      {Data: []byte("API_KEY=local\n")},
+		"apps/web/package.json":   {Data: []byte("{}\n")},
+		"docker-compose.yml.tmpl": {Data: []byte("services: {}\n")},
+	}
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	cfg.DBConnection.Password = "hunter2"
+
+	if err := ScaffoldFromFS(cfg, Options{}, source, nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+
+	manifest, err := ReadManifest(cfg.Destination)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if manifest.GeneratorVersion != version.Version {
+		t.Fatalf("expected generator version %s, got %s", version.Version, manifest.GeneratorVersion)
+	}
+	wantHash, err := HashTemplate(source)
+	if err != nil {
+		t.Fatalf("hash template: %v", err)
+	}
+	if manifest.TemplateHash != wantHash {
+		t.Fatalf("expected template hash %s, got %s", wantHash, manifest.TemplateHash)
+	}
+	if manifest.Config.Name != "demo" || manifest.Config.Database.Password != redacted {
+		t.Fatalf("expected redacted config, got %+v", manifest.Config.Database)
+	}
+	raw := mustReadFile(t, ManifestPath(cfg.Destination))
+	if strings.Contains(raw, "hunter2") {
+		t.Fatalf("manifest must not contain secrets")
+	}
+
+	readme := mustReadFile(t, filepath.Join(cfg.Destination, "README.md"))
+	if manifest.Files["README.md"] != Checksum([]byte(readme)) {
+		t.Fatalf("expected checksum of the rendered README, got %v", manifest.Files)
+	}
+	if _, ok := manifest.Files["apps/api/.env"]; ok {
+		t.Fatalf("generated .env files should not be tracked")
+	}
+	if len(manifest.Files) != 5 {
+		t.Fatalf("expected 5 tracked files, got %v", manifest.Files)
+	}
+}
+
+func TestManifestVerifyFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "kept.txt"), []byte("kept"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "edited.txt"), []byte("edited"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	manifest := Manifest{Files: map[string]string{
+		"kept.txt":   Checksum([]byte("kept")),
+		"edited.txt": Checksum([]byte("original")),
+		"gone.txt":   Checksum([]byte("gone")),
+	}}
+
+	statuses, err := manifest.VerifyFiles(root)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	want := map[string]FileStatus{"kept.txt": FileUnchanged, "edited.txt": FileModified, "gone.txt": FileMissing}
+	for path, status := range want {
+		if statuses[path] != status {
+			t.Fatalf("expected %s to be %s, got %s", path, status, statuses[path])
+		}
+	}
+}
+
+func TestRedactConfigFileDoesNotMutateInput(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage = StorageConfig{Type: StorageS3, S3: &S3Config{AccessKey: "ak", SecretKey: "sk"}}
+	file := ConfigFileFrom(cfg)
+
+	got := RedactConfigFile(file)
+	if got.Storage.S3.SecretKey != redacted || got.Database.Password != redacted {
+		t.Fatalf("expected secrets to be redacted, got %+v", got.Storage.S3)
+	}
+	if file.Storage.S3.SecretKey != "sk" || file.Database.Password != "postgres" {
+		t.Fatalf("redaction must not modify the original config")
+	}
+}
+
+func TestApplyRedactedConfigKeepsCurrentSecrets(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DBConnection.Password = "s3cret"
+	cfg.Storage = StorageConfig{Type: StorageS3, S3: &S3Config{Endpoint: "https://s3.example.com", AccessKey: "ak", SecretKey: "sk"}}
+	file := RedactConfigFile(ConfigFileFrom(cfg))
+
+	got := DefaultConfig()
+	file.Apply(&got)
+	if got.DBConnection.Password != "postgres" {
+		t.Fatalf("expected the redacted password to keep the default, got %q", got.DBConnection.Password)
+	}
+	if got.Storage.S3.SecretKey != "" || got.Storage.S3.AccessKey != "" || got.Storage.S3.Endpoint != "https://s3.example.com" {
+		t.Fatalf("expected only the redacted s3 keys to stay unset, got %+v", got.Storage.S3)
+	}
+}
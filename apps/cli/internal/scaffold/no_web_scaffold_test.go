@@ -18,16 +18,17 @@ func TestScaffoldFromFS_NoWebOutput(t *testing.T) {
 	cfg.InitGit = false
 
 	fsys := fstest.MapFS{
-		"README.md.tmpl":           &fstest.MapFile{Data: []byte("intro\n{{IF_INCLUDE_WEB}}apps/web\n## Web (apps/web)\n{{END_IF_INCLUDE_WEB}}")},
-		"AGENTS.md.tmpl":           &fstest.MapFile{Data: []byte("ctx\n{{IF_INCLUDE_WEB}}App #2: Web (apps/web)\n{{END_IF_INCLUDE_WEB}}")},
-		"package.json.tmpl":        &fstest.MapFile{Data: []byte("{\n\"scripts\": {\n\"api:test\": \"x\",\n{{IF_INCLUDE_WEB}}\"ui:shadcn:add\": \"z\",\n\"web:test\": \"y\",\n{{END_IF_INCLUDE_WEB}}\"ui\": \"z\"\n}\n}")},
-		"docker-compose.yml.tmpl":  &fstest.MapFile{Data: []byte("services:\n  api: {}\n{{IF_INCLUDE_WEB}}  web: {}\n{{END_IF_INCLUDE_WEB}}")},
+		"README.md.tmpl":           &fstest.MapFile{Data: []byte("intro\n{{IF web}}apps/web\n## Web (apps/web)\n{{END}}")},
+		"AGENTS.md.tmpl":           &fstest.MapFile{Data: []byte("ctx\n{{IF web}}App #2: Web (apps/web)\n{{END}}")},
+		"package.json.tmpl":        &fstest.MapFile{Data: []byte("{\n\"scripts\": {\n\"api:test\": \"x\",\n{{IF web}}\"ui:shadcn:add\": \"z\",\n\"web:test\": \"y\",\n{{END}}\"ui\": \"z\"\n}\n}")},
+		"docker-compose.yml.tmpl":  &fstest.MapFile{Data: []byte("services:\n  api: {}\n{{IF web}}  web: {}\n{{END}}")},
 		"apps/web/index.html.tmpl": &fstest.MapFile{Data: []byte("web")},
 		"packages/ui/package.json": &fstest.MapFile{Data: []byte("ui")},
 		"apps/api/main.go":         &fstest.MapFile{Data: []byte("api")},
+		TemplateManifestPath:       &fstest.MapFile{Data: []byte(webFeatureManifest)},
 	}
 
-	if err := ScaffoldFromFS(cfg, true, fsys, nil); err != nil {
+	if err := ScaffoldFromFS(cfg, Options{Resolve: ConflictOverwrite.Resolver()}, fsys, nil); err != nil {
 		t.Fatalf("scaffold failed: %v", err)
 	}
 
@@ -84,15 +85,15 @@ func TestScaffoldFromFS_WithWebOutput(t *testing.T) {
 	cfg.InitGit = false
 
 	fsys := fstest.MapFS{
-		"README.md.tmpl":           &fstest.MapFile{Data: []byte("{{IF_INCLUDE_WEB}}apps/web{{END_IF_INCLUDE_WEB}}")},
-		"AGENTS.md.tmpl":           &fstest.MapFile{Data: []byte("{{IF_INCLUDE_WEB}}App #2: Web (apps/web){{END_IF_INCLUDE_WEB}}")},
-		"package.json.tmpl":        &fstest.MapFile{Data: []byte("{{IF_INCLUDE_WEB}}\"ui:shadcn:add\": \"z\",\"web:test\": \"y\"{{END_IF_INCLUDE_WEB}}")},
-		"docker-compose.yml.tmpl":  &fstest.MapFile{Data: []byte("services:\n{{IF_INCLUDE_WEB}}  web: {}\n{{END_IF_INCLUDE_WEB}}")},
+		"README.md.tmpl":           &fstest.MapFile{Data: []byte("{{IF web}}apps/web{{END}}")},
+		"AGENTS.md.tmpl":           &fstest.MapFile{Data: []byte("{{IF web}}App #2: Web (apps/web){{END}}")},
+		"package.json.tmpl":        &fstest.MapFile{Data: []byte("{{IF web}}\"ui:shadcn:add\": \"z\",\"web:test\": \"y\"{{END}}")},
+		"docker-compose.yml.tmpl":  &fstest.MapFile{Data: []byte("services:\n{{IF web}}  web: {}\n{{END}}")},
 		"apps/web/index.html.tmpl": &fstest.MapFile{Data: []byte("web")},
 		"packages/ui/package.json": &fstest.MapFile{Data: []byte("ui")},
 	}
 
-	if err := ScaffoldFromFS(cfg, true, fsys, nil); err != nil {
+	if err := ScaffoldFromFS(cfg, Options{Resolve: ConflictOverwrite.Resolver()}, fsys, nil); err != nil {
 		t.Fatalf("scaffold failed: %v", err)
 	}
 
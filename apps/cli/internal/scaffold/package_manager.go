@@ -0,0 +1,69 @@
+package scaffold
+
+var PackageManagers = []PackageManager{PackageBun, PackageNPM, PackagePNPM, PackageYarn}
+
+// packageManagerTool describes how the generated project drives a package
+// manager. The fields fill the {{PM_*}} template tokens.
+type packageManagerTool struct {
+	// title is the display name used in prose.
+	title string
+	// spec pins the version in the root package.json "packageManager" field.
+	spec string
+	// exec runs a package binary without installing it.
+	exec string
+	// ciInstall installs exactly what the lock file says.
+	ciInstall string
+	lockfile  string
+	// workspaceVersion is the version range of workspace dependencies.
+	workspaceVersion string
+}
+
+var packageManagerTools = map[PackageManager]packageManagerTool{
+	PackageBun: {
+		title:            "Bun",
+		spec:             "bun@1.2.13",
+		exec:             "bunx",
+		ciInstall:        "bun install --frozen-lockfile",
+		lockfile:         "bun.lock",
+		workspaceVersion: "workspace:*",
+	},
+	PackageNPM: {
+		title:     "npm",
+		spec:      "npm@10.9.2",
+		exec:      "npx",
+		ciInstall: "npm ci",
+		lockfile:  "package-lock.json",
+		// npm has no workspace: protocol; "*" resolves to the local package.
+		workspaceVersion: "*",
+	},
+	PackagePNPM: {
+		title:            "pnpm",
+		spec:             "pnpm@10.12.1",
+		exec:             "pnpm dlx",
+		ciInstall:        "pnpm install --frozen-lockfile",
+		lockfile:         "pnpm-lock.yaml",
+		workspaceVersion: "workspace:*",
+	},
+	PackageYarn: {
+		title:            "Yarn",
+		spec:             "yarn@4.9.2",
+		exec:             "yarn dlx",
+		ciInstall:        "yarn install --immutable",
+		lockfile:         "yarn.lock",
+		workspaceVersion: "workspace:*",
+	},
+}
+
+// packageManagerTokens returns the template tokens for pm.
+func packageManagerTokens(pm PackageManager) map[string]string {
+	tool := packageManagerTools[pm]
+	return map[string]string{
+		"{{PM}}":                string(pm),
+		"{{PM_TITLE}}":          tool.title,
+		"{{PM_SPEC}}":           tool.spec,
+		"{{PM_EXEC}}":           tool.exec,
+		"{{PM_INSTALL_CI}}":     tool.ciInstall,
+		"{{PM_LOCKFILE}}":       tool.lockfile,
+		"{{WORKSPACE_VERSION}}": tool.workspaceVersion,
+	}
+}
@@ -0,0 +1,210 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// PlannedFile is a file that scaffolding would write.
+type PlannedFile struct {
+	Path    string
+	Content []byte
+	// Conditional is set when the content depends on template condition blocks.
+	Conditional bool
+	// Generated is set for .env files generated from a sibling .env.example.
+	Generated bool
+	// EnvOverrides lists the keys of a generated .env file set from the config.
+	EnvOverrides []string
+}
+
+// Plan is the in-memory result of scaffolding, used for dry runs.
+type Plan struct {
+	Files []PlannedFile
+	// Excluded lists the template paths left out by the configuration. Only
+	// the topmost excluded directory is listed.
+	Excluded []string
+}
+
+// PlanProject renders source for cfg in memory without touching the disk.
+// The generation manifest is not part of the plan.
+func PlanProject(cfg ScaffoldConfiguration, source fs.FS, envOverrides map[string]map[string]string) (Plan, error) {
+	var plan Plan
+	features, err := EnableFeatures(cfg, source)
+	if err != nil {
+		return plan, err
+	}
+	transform := renderTransform(cfg, features)
+	files := map[string]PlannedFile{}
+
+	err = fs.WalkDir(source, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+		if DefaultSkip(path) || isTemplateMeta(path) || features.Skip(path) {
+			if features.Skip(path) && !DefaultSkip(path) {
+				plan.Excluded = append(plan.Excluded, stripTemplateSuffix(path))
+			}
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		raw, err := fs.ReadFile(source, path)
+		if err != nil {
+			return err
+		}
+		content, err := transform(path, raw)
+		if err != nil {
+			return err
+		}
+		outPath := stripTemplateSuffix(path)
+		files[outPath] = PlannedFile{
+			Path:        outPath,
+			Content:     content,
+			Conditional: strings.HasSuffix(path, ".tmpl") && HasTemplateConditions(string(raw)),
+		}
+		return nil
+	})
+	if err != nil {
+		return plan, err
+	}
+	if err := features.checkTargets(func(path string) bool { _, ok := files[path]; return ok }); err != nil {
+		return plan, err
+	}
+
+	if envOverrides == nil {
+		envOverrides = EnvOverridesFromConfig(cfg)
+	}
+	for path, example := range files {
+		if !strings.HasSuffix(path, ".env.example") {
+			continue
+		}
+		overrides := envOverrides[path]
+		keys := make([]string, 0, len(overrides))
+		for key := range overrides {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		target := strings.TrimSuffix(path, ".example")
+		files[target] = PlannedFile{
+			Path:         target,
+			Content:      []byte(MergeEnvExample(string(example.Content), overrides)),
+			Generated:    true,
+			EnvOverrides: keys,
+		}
+	}
+
+	for _, file := range files {
+		plan.Files = append(plan.Files, file)
+	}
+	sort.Slice(plan.Files, func(i, j int) bool { return plan.Files[i].Path < plan.Files[j].Path })
+	return plan, nil
+}
+
+// DiffPlan returns a unified diff from the directory dir to the planned
+// files. Files only present in dir are shown as deleted, except for VCS,
+// dependency and build output directories.
+func DiffPlan(plan Plan, dir string) (string, error) {
+	planned := map[string]bool{}
+	var b strings.Builder
+	for _, file := range plan.Files {
+		planned[file.Path] = true
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		fromName := "a/" + file.Path
+		if errors.Is(err, fs.ErrNotExist) {
+			current, fromName = nil, "/dev/null"
+		} else if err != nil {
+			return "", err
+		}
+		if err := writeUnifiedDiff(&b, fromName, "b/"+file.Path, current, file.Content); err != nil {
+			return "", err
+		}
+	}
+
+	var onlyInDir []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if diffIgnoredDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !planned[rel] && !DefaultSkip(rel) {
+			onlyInDir = append(onlyInDir, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, rel := range onlyInDir {
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		if err := writeUnifiedDiff(&b, "a/"+rel, "/dev/null", current, nil); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+var diffIgnoredDirs = map[string]bool{
+	".git":         true,
+	ManifestDir:    true,
+	"node_modules": true,
+	".turbo":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+func writeUnifiedDiff(b *strings.Builder, fromName, toName string, from, to []byte) error {
+	if string(from) == string(to) {
+		return nil
+	}
+	if bytes.IndexByte(from, 0) >= 0 || bytes.IndexByte(to, 0) >= 0 {
+		fmt.Fprintf(b, "Binary files %s and %s differ\n", fromName, toName)
+		return nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(from),
+		B:        diffLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	b.WriteString(diff)
+	return nil
+}
+
+func diffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(content))
+}
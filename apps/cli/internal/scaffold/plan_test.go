@@ -0,0 +1,116 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func planSource() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md.tmpl":            {Data: []byte("# {{PROJECT_NAME}}\n{{IF web}}web\n{{END}}")},
+		"apps/api/main.go":          {Data: []byte("package main\n")},
+		"apps/api/.env.example":     {Data: []byte("API_PRIMARY.APP_NAME=\nAPI_OTHER=1\n")},
+		"apps/web/index.html":       {Data: []byte("<html></html>\n")},
+		"docker-compose.yml.tmpl":   {Data: []byte("services: {}\n")},
+		"node_modules/pkg/index.js": {Data: []byte("ignored\n")},
+		TemplateManifestPath:        {Data: []byte(webFeatureManifest)},
+	}
+}
+
+func TestPlanProject(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.IncludeWeb = false
+
+	plan, err := PlanProject(cfg, planSource(), map[string]map[string]string{
+		"apps/api/.env.example": {"API_PRIMARY.APP_NAME": `"demo"`},
+	})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	files := map[string]PlannedFile{}
+	var paths []string
+	for _, file := range plan.Files {
+		files[file.Path] = file
+		paths = append(paths, file.Path)
+	}
+	wantPaths := []string{"README.md", "apps/api/.env", "apps/api/.env.example", "apps/api/main.go", "docker-compose.yml"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("expected %v, got %v", wantPaths, paths)
+	}
+	if !reflect.DeepEqual(plan.Excluded, []string{"apps/web"}) {
+		t.Fatalf("expected apps/web to be excluded, got %v", plan.Excluded)
+	}
+
+	readme := files["README.md"]
+	if !readme.Conditional || string(readme.Content) != "# demo\n" {
+		t.Fatalf("expected rendered conditional README, got %q", readme.Content)
+	}
+	if files["docker-compose.yml"].Conditional {
+		t.Fatalf("expected docker-compose.yml without conditions to be unmarked")
+	}
+
+	env := files["apps/api/.env"]
+	if !env.Generated || !reflect.DeepEqual(env.EnvOverrides, []string{"API_PRIMARY.APP_NAME"}) {
+		t.Fatalf("expected generated .env with overridden keys, got %+v", env)
+	}
+	if !strings.Contains(string(env.Content), `API_PRIMARY.APP_NAME="demo"`) {
+		t.Fatalf("expected override in .env content, got %q", env.Content)
+	}
+}
+
+func TestPlanProjectMatchesScaffold(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+
+	if err := ScaffoldFromFS(cfg, Options{}, planSource(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	plan, err := PlanProject(cfg, planSource(), nil)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	diff, err := DiffPlan(plan, cfg.Destination)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if diff != "" {
+		t.Fatalf("expected plan to match the scaffolded project, got diff:\n%s", diff)
+	}
+}
+
+func TestDiffPlan(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "changed.txt"), []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "stale.txt"), []byte("stale\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	plan := Plan{Files: []PlannedFile{
+		{Path: "changed.txt", Content: []byte("one\n2\n")},
+		{Path: "new.txt", Content: []byte("new\n")},
+	}}
+
+	diff, err := DiffPlan(plan, dir)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	for _, want := range []string{
+		"--- a/changed.txt\n+++ b/changed.txt\n", "-two\n+2\n",
+		"--- /dev/null\n+++ b/new.txt\n", "+new\n",
+		"--- a/stale.txt\n+++ /dev/null\n", "-stale\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Preset is a named set of config file keys. Presets are applied on top of
+// the defaults, before the config file and the flags.
+type Preset struct {
+	Name        string
+	Description string
+	// Path is the file of a user preset; built-in presets have none.
+	Path   string
+	Config ConfigFile
+}
+
+var presetNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+func builtinPreset(name, description string, web, docker bool, observability ObservabilityProvider) Preset {
+	return Preset{Name: name, Description: description, Config: ConfigFile{
+		Version:       ConfigFileVersion,
+		Web:           &web,
+		Docker:        &docker,
+		Observability: string(observability),
+		Storage:       &StorageConfigFile{Type: string(StorageLocal), Local: &LocalStorageConfigFile{Path: "storage"}},
+	}}
+}
+
+// BuiltinPresets are the presets that ship with the CLI.
+func BuiltinPresets() []Preset {
+	return []Preset{
+		builtinPreset("minimal", "API only, without Docker Compose or observability", false, false, ObservabilityNone),
+		builtinPreset("api-only", "API with Docker Compose, no web app", false, true, ObservabilityNone),
+		builtinPreset("full", "API and web app with Docker Compose", true, true, ObservabilityNone),
+		builtinPreset("full-observability", "API and web app with Docker Compose and the Grafana stack", true, true, ObservabilityGrafanaOSS),
+	}
+}
+
+// DefaultPresetDir is where user presets are kept:
+// $XDG_CONFIG_HOME/gokickstart/presets, or ~/.config/gokickstart/presets.
+func DefaultPresetDir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gokickstart", "presets"), nil
+}
+
+// LoadPresets returns the built-in presets followed by the *.yaml and *.yml
+// presets in dir. A user preset named like a built-in one replaces it.
+func LoadPresets(dir string) ([]Preset, error) {
+	presets := BuiltinPresets()
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return presets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		file, err := LoadConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		preset := Preset{Name: strings.TrimSuffix(entry.Name(), ext), Description: file.Description, Path: path, Config: file}
+		replaced := false
+		for i := range presets {
+			if presets[i].Name == preset.Name {
+				presets[i], replaced = preset, true
+			}
+		}
+		if !replaced {
+			presets = append(presets, preset)
+		}
+	}
+	return presets, nil
+}
+
+// FindPreset returns the preset called name from presets.
+func FindPreset(presets []Preset, name string) (Preset, error) {
+	names := make([]string, 0, len(presets))
+	for _, preset := range presets {
+		if preset.Name == name {
+			return preset, nil
+		}
+		names = append(names, preset.Name)
+	}
+	return Preset{}, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Apply overlays the preset onto cfg.
+func (p Preset) Apply(cfg *ScaffoldConfiguration) {
+	p.Config.Apply(cfg)
+}
+
+// CheckPresetName reports whether name can be saved as a preset file.
+func CheckPresetName(name string) error {
+	if !presetNameRe.MatchString(name) {
+		return fmt.Errorf("invalid preset name %q (use lowercase letters, digits and dashes)", name)
+	}
+	return nil
+}
+
+// SavePreset writes cfg to dir as the user preset called name. The project
+// name, module, description and homepage are left out so the preset fits any
+// project.
+func SavePreset(dir, name string, cfg ScaffoldConfiguration) (string, error) {
+	if err := CheckPresetName(name); err != nil {
+		return "", err
+	}
+	cfg.ProjectName = ""
+	cfg.ModulePath = ""
+	cfg.Metadata.Description = ""
+	cfg.Metadata.Homepage = ""
+	path := filepath.Join(dir, name+".yaml")
+	return path, SaveConfigFile(path, cfg)
+}
@@ -0,0 +1,84 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinPresetsAreValid(t *testing.T) {
+	for _, preset := range BuiltinPresets() {
+		cfg := DefaultConfig()
+		cfg.ProjectName = "demo"
+		cfg.ModulePath = "github.com/acme/demo"
+		preset.Apply(&cfg)
+		if err := ValidateConfig(cfg); err != nil {
+			t.Fatalf("preset %s: %v", preset.Name, err)
+		}
+	}
+	presets := BuiltinPresets()
+	full, err := FindPreset(presets, "full-observability")
+	if err != nil {
+		t.Fatalf("find preset: %v", err)
+	}
+	cfg := DefaultConfig()
+	full.Apply(&cfg)
+	if !cfg.IncludeWeb || cfg.Observability != ObservabilityGrafanaOSS {
+		t.Fatalf("expected the web app and grafana-oss, got %+v", cfg)
+	}
+}
+
+func TestLoadPresetsReadsUserPresets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "minimal.yaml"), []byte("version: 1\ndescription: Our minimal\nweb: true\n"), 0o644); err != nil {
+		t.Fatalf("write preset: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a preset\n"), 0o644); err != nil {
+		t.Fatalf("write notes: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Observability = ObservabilityOTLP
+	cfg.Metadata = ProjectMetadata{Description: "Demo app", Author: "Acme", Homepage: "https://acme.dev", License: LicenseApache}
+	path, err := SavePreset(dir, "team", cfg)
+	if err != nil {
+		t.Fatalf("save preset: %v", err)
+	}
+	if path != filepath.Join(dir, "team.yaml") {
+		t.Fatalf("unexpected preset path %s", path)
+	}
+
+	presets, err := LoadPresets(dir)
+	if err != nil {
+		t.Fatalf("load presets: %v", err)
+	}
+	if got := len(presets); got != len(BuiltinPresets())+1 {
+		t.Fatalf("expected the built-ins and one new preset, got %d", got)
+	}
+	minimal, _ := FindPreset(presets, "minimal")
+	if minimal.Description != "Our minimal" || minimal.Path == "" {
+		t.Fatalf("expected the user preset to replace the built-in, got %+v", minimal)
+	}
+	team, err := FindPreset(presets, "team")
+	if err != nil {
+		t.Fatalf("find preset: %v", err)
+	}
+	got := DefaultConfig()
+	team.Apply(&got)
+	if got.Observability != ObservabilityOTLP || got.ProjectName != "my-app" {
+		t.Fatalf("expected the saved options without the project name, got %+v", got)
+	}
+	if got.Metadata != (ProjectMetadata{Author: "Acme", License: LicenseApache}) {
+		t.Fatalf("expected the author and license without project-specific metadata, got %+v", got.Metadata)
+	}
+
+	if _, err := FindPreset(presets, "nope"); err == nil || !strings.Contains(err.Error(), "available: minimal") {
+		t.Fatalf("expected the available presets to be listed, got %v", err)
+	}
+	if _, err := SavePreset(dir, "../escape", cfg); err == nil {
+		t.Fatalf("expected an invalid preset name to be rejected")
+	}
+}
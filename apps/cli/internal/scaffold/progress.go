@@ -0,0 +1,155 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// Phase is a stage of ScaffoldFromFS.
+type Phase string
+
+const (
+	// PhaseRender renders the template files and the generation manifest.
+	PhaseRender Phase = "render"
+	// PhaseEnv writes a .env next to every .env.example.
+	PhaseEnv Phase = "env"
+	// PhaseSteps runs the post-generation steps.
+	PhaseSteps Phase = "steps"
+	// PhaseGit initializes the repository and makes the first commit.
+	PhaseGit Phase = "git"
+	// PhasePlace moves the project into its destination.
+	PhasePlace Phase = "place"
+)
+
+type EventKind string
+
+const (
+	EventPhaseStart EventKind = "phase_start"
+	EventPhaseEnd   EventKind = "phase_end"
+	// EventFile reports a file written in the render and env phases, or an
+	// existing file and its resolution in the place phase.
+	EventFile       EventKind = "file"
+	EventStepStart  EventKind = "step_start"
+	EventStepOutput EventKind = "step_output"
+	// EventWarning reports something that did not go as configured without
+	// failing the generation.
+	EventWarning EventKind = "warning"
+)
+
+// Event is reported to Options.Observe while a project is generated.
+type Event struct {
+	Kind  EventKind `json:"event"`
+	Phase Phase     `json:"phase"`
+	// Path is the slash-separated project path of a file event.
+	Path       string     `json:"path,omitempty"`
+	Resolution Resolution `json:"resolution,omitempty"`
+	// Done counts the files written so far in the phase. Total is only known
+	// in the render phase.
+	Done  int `json:"done,omitempty"`
+	Total int `json:"total,omitempty"`
+	// Step is set on step events; Command is its command line.
+	Step    string `json:"step,omitempty"`
+	Command string `json:"command,omitempty"`
+	Line    string `json:"line,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// PhaseError reports the phase in which generation failed. Step failures are
+// reported as *StepError instead.
+type PhaseError struct {
+	Phase Phase
+	Err   error
+}
+
+func (e *PhaseError) Error() string { return e.Err.Error() }
+
+func (e *PhaseError) Unwrap() error { return e.Err }
+
+// Error codes for machine-readable output.
+const (
+	CodeInvalidConfig       = "invalid_config"
+	CodeDestinationNotEmpty = "destination_not_empty"
+	CodeTemplate            = "template_error"
+	CodeStepFailed          = "step_failed"
+	CodeRenderFailed        = "render_failed"
+	CodeEnvFailed           = "env_failed"
+	CodeGitFailed           = "git_failed"
+	CodePlaceFailed         = "place_failed"
+	CodeUnknown             = "error"
+)
+
+// ErrorCode classifies an error returned while configuring or generating a
+// project.
+func ErrorCode(err error) string {
+	var configErr *ConfigError
+	var stepErr *StepError
+	var templateErr *TemplateError
+	var phaseErr *PhaseError
+	switch {
+	case errors.As(err, &configErr):
+		return CodeInvalidConfig
+	case errors.Is(err, ErrDestinationNotEmpty):
+		return CodeDestinationNotEmpty
+	case errors.As(err, &stepErr):
+		return CodeStepFailed
+	case errors.As(err, &templateErr):
+		return CodeTemplate
+	case errors.As(err, &phaseErr):
+		switch phaseErr.Phase {
+		case PhaseRender:
+			return CodeRenderFailed
+		case PhaseEnv:
+			return CodeEnvFailed
+		case PhaseGit:
+			return CodeGitFailed
+		case PhasePlace:
+			return CodePlaceFailed
+		}
+	}
+	return CodeUnknown
+}
+
+// observer wraps Options.Observe so phases can report without nil checks.
+type observer func(Event)
+
+func (o observer) emit(event Event) {
+	if o != nil {
+		o(event)
+	}
+}
+
+// run reports phase p around fn. An error of fn is wrapped in a *PhaseError
+// unless it is a *StepError, and the end of a failed phase is not reported.
+func (o observer) run(p Phase, fn func() error) error {
+	o.emit(Event{Kind: EventPhaseStart, Phase: p})
+	if err := fn(); err != nil {
+		var stepErr *StepError
+		if errors.As(err, &stepErr) {
+			return err
+		}
+		return &PhaseError{Phase: p, Err: err}
+	}
+	o.emit(Event{Kind: EventPhaseEnd, Phase: p})
+	return nil
+}
+
+// countFiles counts the files RenderFS writes for source.
+func countFiles(source fs.FS, skip func(string) bool) (int, error) {
+	count := 0
+	err := fs.WalkDir(source, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == "." {
+			return err
+		}
+		if skip != nil && skip(path) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,101 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScaffoldFromFSReportsProgress(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "demo")
+	cfg := stageConfig(t, dest)
+	cfg.InitGit = true
+	initGitRepo = func(string, GitOptions) error { return nil }
+	t.Cleanup(func() { initGitRepo = InitGitRepo })
+
+	var events []Event
+	opts := Options{Observe: func(event Event) { events = append(events, event) }}
+	if err := ScaffoldFromFS(cfg, opts, stageSource(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+
+	var phases []string
+	files := map[Phase][]string{}
+	for _, event := range events {
+		switch event.Kind {
+		case EventPhaseStart, EventPhaseEnd:
+			phases = append(phases, fmt.Sprintf("%s %s", event.Kind, event.Phase))
+		case EventFile:
+			files[event.Phase] = append(files[event.Phase], event.Path)
+			if event.Phase == PhaseRender && event.Total != 4 {
+				t.Fatalf("expected 4 files to render, got %+v", event)
+			}
+			if event.Done != len(files[event.Phase]) {
+				t.Fatalf("expected done to count the files of the phase, got %+v", event)
+			}
+		}
+	}
+	wantPhases := []string{
+		"phase_start render", "phase_end render",
+		"phase_start env", "phase_end env",
+		"phase_start git", "phase_end git",
+		"phase_start place", "phase_end place",
+	}
+	if !reflect.DeepEqual(phases, wantPhases) {
+		t.Fatalf("expected phases %v, got %v", wantPhases, phases)
+	}
+	if got := len(files[PhaseRender]); got != 4 || files[PhaseRender][3] != ".gokickstart/manifest.json" {
+		t.Fatalf("expected the rendered files and the manifest, got %v", files[PhaseRender])
+	}
+	if want := []string{"apps/api/.env"}; !reflect.DeepEqual(files[PhaseEnv], want) {
+		t.Fatalf("expected env files %v, got %v", want, files[PhaseEnv])
+	}
+}
+
+func TestScaffoldFromFSReportsConflictResolutions(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "demo")
+	writeTestFile(t, filepath.Join(dest, "README.md"), "original readme\n")
+
+	var resolutions []string
+	opts := Options{
+		Resolve: ConflictSkip.Resolver(),
+		Observe: func(event Event) {
+			if event.Kind == EventFile && event.Phase == PhasePlace {
+				resolutions = append(resolutions, event.Path+"="+string(event.Resolution))
+			}
+		},
+	}
+	if err := ScaffoldFromFS(stageConfig(t, dest), opts, stageSource(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	if want := []string{"README.md=keep"}; !reflect.DeepEqual(resolutions, want) {
+		t.Fatalf("expected %v, got %v", want, resolutions)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	parent := t.TempDir()
+	source := stageSource()
+	source["broken.txt.tmpl"] = &fstest.MapFile{Data: []byte("{{IF nope}}x{{END}}")}
+	renderErr := ScaffoldFromFS(stageConfig(t, filepath.Join(parent, "demo")), Options{}, source, nil)
+
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{renderErr, CodeTemplate},
+		{&ConfigError{Errors: []FieldError{{Field: "name", Message: "is required"}}}, CodeInvalidConfig},
+		{fmt.Errorf("%w: /tmp/demo", ErrDestinationNotEmpty), CodeDestinationNotEmpty},
+		{fmt.Errorf("wrapped: %w", &StepError{Step: Step{Name: StepTidy}, Err: errors.New("exit status 1")}), CodeStepFailed},
+		{fmt.Errorf("initialize git repository: %w", &PhaseError{Phase: PhaseGit, Err: errors.New("no git")}), CodeGitFailed},
+		{errors.New("boom"), CodeUnknown},
+	}
+	for _, c := range cases {
+		if got := ErrorCode(c.err); got != c.want {
+			t.Fatalf("ErrorCode(%v) = %s, want %s", c.err, got, c.want)
+		}
+	}
+}
@@ -72,7 +72,7 @@ func stripTemplateSuffix(path string) string {
 func DefaultSkip(path string) bool {
 	base := filepath.Base(path)
 	for _, glob := range DefaultIgnoreGlobs {
-		if match, _ := filepath.Match(strings.ReplaceAll(glob, "**/", ""), path); match {
+		if matchIgnoreGlob(glob, path) {
 			if strings.HasPrefix(base, ".env.example") {
 				continue
 			}
@@ -81,3 +81,20 @@ func DefaultSkip(path string) bool {
 	}
 	return false
 }
+
+// matchIgnoreGlob matches path against glob. Directory globs such as
+// **/node_modules/** match the directory at any depth, which matters for
+// templates read from a working copy on disk.
+func matchIgnoreGlob(glob, path string) bool {
+	pattern := strings.ReplaceAll(glob, "**/", "")
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		for _, segment := range strings.Split(path, "/") {
+			if match, _ := filepath.Match(dir, segment); match {
+				return true
+			}
+		}
+		return false
+	}
+	match, _ := filepath.Match(pattern, path)
+	return match
+}
@@ -1,12 +1,16 @@
 package scaffold
 
 import (
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/templatesource"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/validate"
 	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/templates"
 )
 
@@ -15,52 +19,237 @@ const (
 	TemplateProjectName = "go-kickstart"
 )
 
-func ScaffoldProject(cfg ScaffoldConfiguration, allowOverwrite bool) error {
-	sub, err := fs.Sub(templates.MonorepoFS, "monorepo")
+// ScaffoldProject generates cfg.Destination from the configured template.
+func ScaffoldProject(cfg ScaffoldConfiguration, opts Options) error {
+	source, err := templatesource.Resolve(cfg.Template, templatesource.Options{})
 	if err != nil {
-		return err
+		return &PhaseError{Phase: PhaseRender, Err: err}
 	}
-	return ScaffoldFromFS(cfg, allowOverwrite, sub, nil)
+	return ScaffoldFromFS(cfg, opts, source.FS, nil)
+}
+
+// EmbeddedTemplate returns the monorepo template bundled with the CLI.
+func EmbeddedTemplate() (fs.FS, error) {
+	return fs.Sub(templates.MonorepoFS, "monorepo")
 }
 
-func ScaffoldFromFS(cfg ScaffoldConfiguration, allowOverwrite bool, source fs.FS, envOverrides map[string]map[string]string) error {
-	if err := EnsureSafeDestination(cfg.Destination, allowOverwrite); err != nil {
+// ScaffoldFromFS renders source into a staging directory next to the
+// destination and only moves the result into place once every step has
+// succeeded. In a non-empty destination, opts.Resolve decides about existing
+// files with different content; files it replaces are restored if a later
+// step fails.
+//
+// The post-generation steps in opts.Steps run before git is initialized so
+// their output (lock files, go.sum) is part of the first commit. Without a
+// git identity the files are staged but not committed. A failing
+// step does not undo the generation: the project is still placed and
+// committed, and the *StepError is returned.
+func ScaffoldFromFS(cfg ScaffoldConfiguration, opts Options, source fs.FS, envOverrides map[string]map[string]string) error {
+	if err := EnsureSafeDestination(cfg.Destination, opts.Resolve != nil); err != nil {
 		return err
 	}
-	replacements := map[string]string{
-		"{{PROJECT_NAME}}":       cfg.ProjectName,
-		"{{PROJECT_NAME_KEBAB}}": toKebabCase(cfg.ProjectName),
-		"{{MODULE_PATH}}":        cfg.ModulePath,
-		TemplateModulePath:       cfg.ModulePath,
-		TemplateProjectName:      cfg.ProjectName,
+	features, err := EnableFeatures(cfg, source)
+	if err != nil {
+		return err
 	}
-	skip := combineSkips(DefaultSkip, ShouldSkipForConfig(cfg))
-	transform := func(path string, content []byte) ([]byte, error) {
-		// Strict templating: only apply token replacement to *.tmpl files.
-		if !strings.HasSuffix(path, ".tmpl") {
-			return content, nil
+	staging, err := newStagingDir(cfg.Destination)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	observe := observer(opts.Observe)
+	gitOpts := gitOptions(cfg, observe)
+	if err := writeProject(cfg, staging, source, features, envOverrides, observe); err != nil {
+		return err
+	}
+
+	// A fresh project is set up and committed in staging. Otherwise the steps
+	// and git run in place so they see the files that were already there.
+	existing, err := validate.IsNonEmptyDir(cfg.Destination)
+	if err != nil {
+		return err
+	}
+	var stepErr error
+	if !existing {
+		stepErr = runSteps(staging, opts)
+		if cfg.InitGit {
+			if err := observe.run(PhaseGit, func() error { return initGitRepo(staging, gitOpts) }); err != nil {
+				return fmt.Errorf("initialize git repository: %w", err)
+			}
 		}
-		rendered := ApplyTemplateConditions(string(content), cfg)
-		rendered = ReplaceTokens(rendered, replacements)
-		return []byte(rendered), nil
 	}
-	if err := RenderFS(source, cfg.Destination, skip, transform); err != nil {
+
+	var placed *placement
+	err = observe.run(PhasePlace, func() error {
+		placed, err = placeStaged(staging, cfg.Destination, opts.Resolve, observe)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if existing {
+		stepErr = runSteps(cfg.Destination, opts)
+	}
+	if cfg.InitGit && existing {
+		gitDir := filepath.Join(cfg.Destination, ".git")
+		_, statErr := os.Stat(gitDir)
+		if err := observe.run(PhaseGit, func() error { return initGitRepo(cfg.Destination, gitOpts) }); err != nil {
+			err = fmt.Errorf("initialize git repository: %w", err)
+			if errors.Is(statErr, fs.ErrNotExist) {
+				_ = os.RemoveAll(gitDir)
+			}
+			return errors.Join(err, placed.rollback())
+		}
+	}
+	if err := placed.commit(); err != nil {
+		return &PhaseError{Phase: PhasePlace, Err: err}
+	}
+	return stepErr
+}
+
+// gitOptions returns the options git is initialized with. Without a git
+// identity to commit with, the files are only staged and a warning is
+// reported.
+func gitOptions(cfg ScaffoldConfiguration, observe observer) GitOptions {
+	opts := cfg.Git
+	if cfg.InitGit && !opts.NoCommit && !hasGitIdentity(opts) {
+		opts.NoCommit = true
+		observe.emit(Event{Kind: EventWarning, Phase: PhaseGit, Message: NoGitIdentityWarning})
+	}
+	return opts
+}
+
+// writeProject renders source into root and writes the generation manifest
+// and the env files.
+func writeProject(cfg ScaffoldConfiguration, root string, source fs.FS, features FeatureSet, envOverrides map[string]map[string]string, observe observer) error {
+	err := observe.run(PhaseRender, func() error {
+		return renderProject(cfg, root, source, features, observe)
+	})
+	if err != nil {
 		return err
 	}
 	if envOverrides == nil {
 		envOverrides = EnvOverridesFromConfig(cfg)
 	}
-	if err := generateEnvFiles(cfg.Destination, envOverrides); err != nil {
+	return observe.run(PhaseEnv, func() error {
+		return generateEnvFiles(root, envOverrides, observe)
+	})
+}
+
+// renderProject renders source into root and writes the manifest, reporting
+// each file with the number written so far.
+func renderProject(cfg ScaffoldConfiguration, root string, source fs.FS, features FeatureSet, observe observer) error {
+	skip := renderSkip(features)
+	total, err := countFiles(source, skip)
+	if err != nil {
 		return err
 	}
-	if cfg.InitGit {
-		if err := InitGitRepo(cfg.Destination); err != nil {
-			return err
+	total++ // the manifest
+	done := 0
+	written := func(path string) {
+		done++
+		observe.emit(Event{Kind: EventFile, Phase: PhaseRender, Path: path, Done: done, Total: total})
+	}
+
+	checksums := map[string]string{}
+	render := renderTransform(cfg, features)
+	transform := func(path string, content []byte) ([]byte, error) {
+		content, err := render(path, content)
+		if err != nil {
+			return nil, err
 		}
+		checksums[stripTemplateSuffix(path)] = Checksum(content)
+		written(stripTemplateSuffix(path))
+		return content, nil
+	}
+	if err := RenderFS(source, root, skip, transform); err != nil {
+		return err
+	}
+	if err := features.checkTargets(func(path string) bool { _, ok := checksums[path]; return ok }); err != nil {
+		return err
+	}
+	dropGeneratedEnvFiles(checksums)
+	templateHash, err := HashTemplate(source)
+	if err != nil {
+		return err
 	}
+	if err := WriteManifest(root, NewManifest(cfg, templateHash, checksums)); err != nil {
+		return err
+	}
+	written(ManifestDir + "/" + ManifestFile)
 	return nil
 }
 
+// RenderProject renders source for cfg in memory, keyed by slash-separated
+// output path. Generated .env files are left out, as in the manifest.
+func RenderProject(cfg ScaffoldConfiguration, source fs.FS) (map[string][]byte, error) {
+	plan, err := PlanProject(cfg, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(plan.Files))
+	for _, file := range plan.Files {
+		if !file.Generated {
+			files[file.Path] = file.Content
+		}
+	}
+	return files, nil
+}
+
+func renderSkip(features FeatureSet) func(string) bool {
+	return combineSkips(DefaultSkip, isTemplateMeta, features.Skip)
+}
+
+// isTemplateMeta reports whether path is in the template's own .gokickstart
+// directory, which describes the template rather than being part of it.
+func isTemplateMeta(path string) bool {
+	return path == ManifestDir || strings.HasPrefix(path, ManifestDir+"/")
+}
+
+func renderTransform(cfg ScaffoldConfiguration, features FeatureSet) TransformFunc {
+	expand := tokenExpander(cfg)
+	return func(path string, content []byte) ([]byte, error) {
+		// Strict templating: only apply token replacement to *.tmpl files.
+		if strings.HasSuffix(path, ".tmpl") {
+			rendered, err := ApplyTemplateConditions(path, string(content), cfg)
+			if err != nil {
+				return nil, err
+			}
+			content = []byte(expand(rendered))
+		}
+		return features.apply(stripTemplateSuffix(path), content, expand)
+	}
+}
+
+// tokenExpander returns the token replacement applied to *.tmpl files.
+func tokenExpander(cfg ScaffoldConfiguration) func(string) string {
+	replacements := map[string]string{
+		"{{PROJECT_NAME}}":       cfg.ProjectName,
+		"{{PROJECT_NAME_KEBAB}}": toKebabCase(cfg.ProjectName),
+		"{{MODULE_PATH}}":        cfg.ModulePath,
+		TemplateModulePath:       cfg.ModulePath,
+		TemplateProjectName:      cfg.ProjectName,
+	}
+	for token, value := range packageManagerTokens(cfg.PackageManager) {
+		replacements[token] = value
+	}
+	for token, value := range metadataTokens(cfg) {
+		replacements[token] = value
+	}
+	return func(s string) string { return ReplaceTokens(s, replacements) }
+}
+
+// dropGeneratedEnvFiles removes .env files that are generated from a sibling
+// .env.example; they hold local values and secrets, so they are not tracked.
+func dropGeneratedEnvFiles[V any](files map[string]V) {
+	for path := range files {
+		if strings.HasSuffix(path, ".env.example") {
+			delete(files, strings.TrimSuffix(path, ".example"))
+		}
+	}
+}
+
 func combineSkips(skips ...func(string) bool) func(string) bool {
 	return func(path string) bool {
 		for _, fn := range skips {
@@ -90,7 +279,8 @@ func toKebabCase(value string) string {
 	return strings.ToLower(value)
 }
 
-func generateEnvFiles(root string, overrides map[string]map[string]string) error {
+func generateEnvFiles(root string, overrides map[string]map[string]string, observe observer) error {
+	done := 0
 	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -114,6 +304,8 @@ func generateEnvFiles(root string, overrides map[string]map[string]string) error
 			if err := os.WriteFile(target, []byte(merged), info.Mode()); err != nil {
 				return err
 			}
+			done++
+			observe.emit(Event{Kind: EventFile, Phase: PhaseEnv, Path: filepath.ToSlash(envFile(key)), Done: done})
 		}
 		return nil
 	})
@@ -0,0 +1,77 @@
+package scaffold
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// Secrets are generated per project so no two projects share credentials.
+// Empty fields keep the placeholders from the .env.example files.
+type Secrets struct {
+	AuthSecretKey string
+	// DBPassword replaces the default password of the Compose database. It is
+	// kept apart from DBConnection so saved config files never contain it.
+	DBPassword    string
+	RedisPassword string
+}
+
+// GeneratedSecret names a generated value and the files it was written to.
+type GeneratedSecret struct {
+	Key   string   `json:"key"`
+	Files []string `json:"files"`
+}
+
+// GenerateSecrets returns random secrets for cfg. The database and Redis
+// passwords are only generated for the Docker Compose services, and the
+// database password only when it is still the default of its type.
+func GenerateSecrets(cfg ScaffoldConfiguration) (Secrets, error) {
+	var secrets Secrets
+	var err error
+	if secrets.AuthSecretKey, err = randomSecret(32); err != nil {
+		return Secrets{}, err
+	}
+	if !cfg.IncludeDocker {
+		return secrets, nil
+	}
+	if cfg.DBConnection.Password == DefaultDBConnection(cfg.DatabaseType).Password {
+		if secrets.DBPassword, err = randomSecret(24); err != nil {
+			return Secrets{}, err
+		}
+	}
+	if secrets.RedisPassword, err = randomSecret(24); err != nil {
+		return Secrets{}, err
+	}
+	return secrets, nil
+}
+
+// randomSecret returns n random bytes as unpadded URL-safe base64, so the
+// value can go into DSNs and URLs without escaping.
+func randomSecret(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Generated lists the secrets that were generated for cfg.
+func (s Secrets) Generated(cfg ScaffoldConfiguration) []GeneratedSecret {
+	compose := func(files ...string) []string {
+		if cfg.IncludeDocker {
+			files = append(files, envFile(composeEnvExample))
+		}
+		return files
+	}
+	var out []GeneratedSecret
+	if s.AuthSecretKey != "" {
+		out = append(out, GeneratedSecret{Key: "API_AUTH.SECRET_KEY", Files: compose(envFile(apiEnvExample))})
+	}
+	if s.DBPassword != "" {
+		out = append(out, GeneratedSecret{Key: "API_DATABASE.PASSWORD", Files: compose(envFile(apiEnvExample))})
+	}
+	if s.RedisPassword != "" {
+		out = append(out, GeneratedSecret{Key: "API_CACHE.REDIS_PASSWORD", Files: compose(envFile(apiEnvExample))})
+	}
+	return out
+}
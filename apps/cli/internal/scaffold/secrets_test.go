@@ -0,0 +1,94 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSecrets(t *testing.T) {
+	cfg := DefaultConfig()
+	first, err := GenerateSecrets(cfg)
+	if err != nil {
+		t.Fatalf("generate secrets: %v", err)
+	}
+	second, err := GenerateSecrets(cfg)
+	if err != nil {
+		t.Fatalf("generate secrets: %v", err)
+	}
+	if first.AuthSecretKey == "" || first.DBPassword == "" || first.RedisPassword == "" {
+		t.Fatalf("expected every secret to be generated, got %+v", first)
+	}
+	if first == second {
+		t.Fatalf("expected different secrets per call")
+	}
+	if len(first.AuthSecretKey) != 43 {
+		t.Fatalf("expected a 32 byte auth secret, got %q", first.AuthSecretKey)
+	}
+
+	cfg.DBConnection.Password = "hunter2"
+	custom, err := GenerateSecrets(cfg)
+	if err != nil {
+		t.Fatalf("generate secrets: %v", err)
+	}
+	if custom.DBPassword != "" {
+		t.Fatalf("expected a chosen database password to be kept")
+	}
+
+	cfg.IncludeDocker = false
+	noDocker, err := GenerateSecrets(cfg)
+	if err != nil {
+		t.Fatalf("generate secrets: %v", err)
+	}
+	if noDocker.AuthSecretKey == "" || noDocker.DBPassword != "" || noDocker.RedisPassword != "" {
+		t.Fatalf("expected only the auth secret without Docker, got %+v", noDocker)
+	}
+	if got := noDocker.Generated(cfg); len(got) != 1 || strings.Join(got[0].Files, ",") != "apps/api/.env" {
+		t.Fatalf("unexpected generated secrets: %+v", got)
+	}
+}
+
+func TestScaffoldProjectWritesSecretsConsistently(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = false
+	secrets, err := GenerateSecrets(cfg)
+	if err != nil {
+		t.Fatalf("generate secrets: %v", err)
+	}
+	cfg.Secrets = secrets
+
+	if err := ScaffoldProject(cfg, Options{}); err != nil {
+		t.Fatalf("scaffold project: %v", err)
+	}
+
+	apiEnv := mustReadFile(t, filepath.Join(cfg.Destination, "apps/api/.env"))
+	composeEnv := mustReadFile(t, filepath.Join(cfg.Destination, ".env"))
+	for _, want := range []struct{ content, line string }{
+		{apiEnv, `API_AUTH.SECRET_KEY="` + secrets.AuthSecretKey + `"`},
+		{apiEnv, `API_DATABASE.PASSWORD="` + secrets.DBPassword + `"`},
+		{apiEnv, `API_CACHE.REDIS_PASSWORD="` + secrets.RedisPassword + `"`},
+		{composeEnv, `API_AUTH_SECRET_KEY="` + secrets.AuthSecretKey + `"`},
+		{composeEnv, `DB_PASSWORD="` + secrets.DBPassword + `"`},
+		{composeEnv, `REDIS_PASSWORD="` + secrets.RedisPassword + `"`},
+	} {
+		if !strings.Contains(want.content, want.line) {
+			t.Fatalf("expected %q in:\n%s", want.line, want.content)
+		}
+	}
+
+	for _, path := range []string{"apps/api/.env.example", ".env.example", "docker-compose.yml"} {
+		content := mustReadFile(t, filepath.Join(cfg.Destination, path))
+		for _, secret := range []string{secrets.AuthSecretKey, secrets.DBPassword, secrets.RedisPassword} {
+			if strings.Contains(content, secret) {
+				t.Fatalf("expected %s to keep placeholders, found a generated secret", path)
+			}
+		}
+	}
+	compose := mustReadFile(t, filepath.Join(cfg.Destination, "docker-compose.yml"))
+	if strings.Contains(compose, `POSTGRES_PASSWORD: "postgres"`) || !strings.Contains(compose, "${DB_PASSWORD") {
+		t.Fatalf("expected compose to read the database password from .env:\n%s", compose)
+	}
+}
@@ -0,0 +1,217 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// newStagingDir creates an empty directory next to dest, on the same file
+// system, so the finished project can be renamed into place.
+func newStagingDir(dest string) (string, error) {
+	parent := filepath.Dir(dest)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(parent, "."+filepath.Base(dest)+".staging-")
+}
+
+// placement records how a staged project was moved into its destination so
+// the move can be undone.
+type placement struct {
+	dest string
+	// renamed is set when the whole staging directory became dest.
+	renamed bool
+	// recreate is set when dest was an empty directory removed for the rename.
+	recreate bool
+
+	backupDir string
+	moved     []movedFile
+	created   []string
+	observe   observer
+}
+
+type movedFile struct {
+	target string
+	// restoreFrom is where the file previously at target was moved, if any.
+	restoreFrom string
+}
+
+// placeStaged moves the staged project into dest. An empty or missing dest is
+// replaced by renaming staging. Otherwise files are moved one by one; resolve
+// decides about existing files with different content, and any file that gets
+// replaced is first moved to a backup directory. If a move fails, everything
+// done so far is rolled back. Existing files are reported to observe with
+// their resolution.
+func placeStaged(staging, dest string, resolve ConflictResolver, observe observer) (*placement, error) {
+	p := &placement{dest: dest, observe: observe}
+	entries, err := os.ReadDir(dest)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) || (err == nil && len(entries) == 0):
+		if err == nil {
+			if err := os.Remove(dest); err != nil {
+				return nil, err
+			}
+			p.recreate = true
+		}
+		if err := os.Rename(staging, dest); err != nil {
+			if p.recreate {
+				_ = os.Mkdir(dest, 0o755)
+			}
+			return nil, err
+		}
+		p.renamed = true
+		return p, nil
+	case err != nil:
+		return nil, err
+	}
+
+	p.backupDir, err = os.MkdirTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".backup-")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.merge(staging, resolve); err != nil {
+		if rollbackErr := p.rollback(); rollbackErr != nil {
+			return nil, errors.Join(err, rollbackErr)
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *placement) merge(staging string, resolve ConflictResolver) error {
+	return filepath.WalkDir(staging, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(staging, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		target := filepath.Join(p.dest, rel)
+		existing, statErr := os.Lstat(target)
+		if statErr != nil && !errors.Is(statErr, fs.ErrNotExist) {
+			return statErr
+		}
+		exists := statErr == nil
+
+		if d.IsDir() {
+			if !exists {
+				if err := os.Mkdir(target, 0o755); err != nil {
+					return err
+				}
+				p.created = append(p.created, target)
+			} else if !existing.IsDir() {
+				return fmt.Errorf("%s exists and is not a directory", target)
+			}
+			return nil
+		}
+
+		if !exists {
+			return p.move(path, movedFile{target: target})
+		}
+		if existing.IsDir() {
+			return fmt.Errorf("%s exists and is a directory", target)
+		}
+		resolution, err := p.resolve(path, target, filepath.ToSlash(rel), resolve)
+		if err != nil {
+			return err
+		}
+		p.observe.emit(Event{Kind: EventFile, Phase: PhasePlace, Path: filepath.ToSlash(rel), Resolution: resolution})
+		switch resolution {
+		case ResolveKeep:
+			return nil
+		case ResolveWriteNew:
+			return p.move(path, movedFile{target: freePath(target + ".new")})
+		case ResolveBackup:
+			saved := freePath(target + ".bak")
+			if err := os.Rename(target, saved); err != nil {
+				return err
+			}
+			return p.move(path, movedFile{target: target, restoreFrom: saved})
+		default:
+			backup := filepath.Join(p.backupDir, rel)
+			if err := os.MkdirAll(filepath.Dir(backup), 0o755); err != nil {
+				return err
+			}
+			if err := os.Rename(target, backup); err != nil {
+				return err
+			}
+			return p.move(path, movedFile{target: target, restoreFrom: backup})
+		}
+	})
+}
+
+// resolve decides about an existing file. Unchanged files and the generation
+// manifest are always replaced.
+func (p *placement) resolve(staged, target, rel string, resolve ConflictResolver) (Resolution, error) {
+	if rel == ManifestDir || strings.HasPrefix(rel, ManifestDir+"/") {
+		return ResolveReplace, nil
+	}
+	current, err := os.ReadFile(target)
+	if err != nil {
+		return "", err
+	}
+	generated, err := os.ReadFile(staged)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(current, generated) {
+		return ResolveReplace, nil
+	}
+	return resolve(rel), nil
+}
+
+func (p *placement) move(staged string, moved movedFile) error {
+	p.moved = append(p.moved, moved)
+	return os.Rename(staged, moved.target)
+}
+
+// rollback restores dest to its state before placeStaged. The backup
+// directory is kept if anything cannot be restored.
+func (p *placement) rollback() error {
+	if p.renamed {
+		if err := os.RemoveAll(p.dest); err != nil {
+			return err
+		}
+		if p.recreate {
+			return os.Mkdir(p.dest, 0o755)
+		}
+		return nil
+	}
+
+	var errs []error
+	for i := len(p.moved) - 1; i >= 0; i-- {
+		moved := p.moved[i]
+		if err := os.Remove(moved.target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		if moved.restoreFrom != "" {
+			if err := os.Rename(moved.restoreFrom, moved.target); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for i := len(p.created) - 1; i >= 0; i-- {
+		if err := os.RemoveAll(p.created[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("restore %s (originals kept in %s): %w", p.dest, p.backupDir, errors.Join(errs...))
+	}
+	return os.RemoveAll(p.backupDir)
+}
+
+// commit drops the backups once the project is complete.
+func (p *placement) commit() error {
+	if p.backupDir == "" {
+		return nil
+	}
+	return os.RemoveAll(p.backupDir)
+}
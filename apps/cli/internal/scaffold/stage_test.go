@@ -0,0 +1,144 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func stageSource() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md.tmpl":        {Data: []byte("# {{PROJECT_NAME}}\n")},
+		"apps/api/main.go":      {Data: []byte("package main\n")},
+		"apps/api/.env.example": {Data: []byte("API_PORT=8080\n")},
+	}
+}
+
+func stageConfig(t *testing.T, dest string) ScaffoldConfiguration {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = dest
+	cfg.InitGit = false
+	return cfg
+}
+
+func TestScaffoldFromFSFailureLeavesNoDestination(t *testing.T) {
+	parent := t.TempDir()
+	cfg := stageConfig(t, filepath.Join(parent, "demo"))
+	source := stageSource()
+	source["apps/api/broken.go.tmpl"] = &fstest.MapFile{Data: []byte("{{IF nope}}x{{END}}")}
+
+	if err := ScaffoldFromFS(cfg, Options{}, source, nil); err == nil {
+		t.Fatalf("expected render error")
+	}
+	assertDirEntries(t, parent, nil)
+}
+
+func TestScaffoldFromFSRestoresOverwrittenFilesOnFailure(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "demo")
+	writeTestFile(t, filepath.Join(dest, "README.md"), "original readme\n")
+	writeTestFile(t, filepath.Join(dest, "notes.txt"), "keep me\n")
+
+	cfg := stageConfig(t, dest)
+	cfg.InitGit = true
+	initGitRepo = func(path string, _ GitOptions) error {
+		if path != dest {
+			t.Fatalf("expected git to run in the destination, got %s", path)
+		}
+		return errors.New("git exploded")
+	}
+	t.Cleanup(func() { initGitRepo = InitGitRepo })
+
+	if err := ScaffoldFromFS(cfg, Options{Resolve: ConflictOverwrite.Resolver()}, stageSource(), nil); err == nil {
+		t.Fatalf("expected git error")
+	}
+	assertFileContent(t, filepath.Join(dest, "README.md"), "original readme\n")
+	assertFileContent(t, filepath.Join(dest, "notes.txt"), "keep me\n")
+	assertDirEntries(t, dest, []string{"README.md", "notes.txt"})
+	assertDirEntries(t, parent, []string{"demo"})
+}
+
+func TestScaffoldFromFSOverwriteKeepsOtherFiles(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "demo")
+	writeTestFile(t, filepath.Join(dest, "README.md"), "original readme\n")
+	writeTestFile(t, filepath.Join(dest, "notes.txt"), "keep me\n")
+
+	if err := ScaffoldFromFS(stageConfig(t, dest), Options{Resolve: ConflictOverwrite.Resolver()}, stageSource(), map[string]map[string]string{}); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	assertFileContent(t, filepath.Join(dest, "README.md"), "# demo\n")
+	assertFileContent(t, filepath.Join(dest, "notes.txt"), "keep me\n")
+	assertFileContent(t, filepath.Join(dest, "apps/api/.env"), "API_PORT=8080\n")
+	assertDirEntries(t, parent, []string{"demo"})
+}
+
+func TestScaffoldFromFSCommitsFreshProjectInStaging(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "demo")
+	cfg := stageConfig(t, dest)
+	cfg.InitGit = true
+	var gitDir string
+	initGitRepo = func(path string, _ GitOptions) error {
+		gitDir = path
+		return os.Mkdir(filepath.Join(path, ".git"), 0o755)
+	}
+	t.Cleanup(func() { initGitRepo = InitGitRepo })
+
+	if err := ScaffoldFromFS(cfg, Options{}, stageSource(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	if gitDir == dest || filepath.Dir(gitDir) != parent {
+		t.Fatalf("expected git to run in a staging directory next to %s, got %s", dest, gitDir)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err != nil {
+		t.Fatalf("expected .git to be moved into place: %v", err)
+	}
+	assertDirEntries(t, parent, []string{"demo"})
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func assertFileContent(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(got) != want {
+		t.Fatalf("%s: expected %q, got %q", path, want, got)
+	}
+}
+
+func assertDirEntries(t *testing.T, dir string, want []string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir %s: %v", dir, err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected entries %v, got %v", dir, want, got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected entries %v, got %v", dir, want, got)
+		}
+	}
+}
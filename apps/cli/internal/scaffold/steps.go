@@ -0,0 +1,170 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Post-generation step names, as accepted by --skip-step.
+const (
+	StepTidy    = "tidy"
+	StepInstall = "install"
+	StepOpenAPI = "openapi"
+	StepEmails  = "emails"
+	StepBuild   = "build"
+	StepVet     = "vet"
+)
+
+var StepNames = []string{StepTidy, StepInstall, StepOpenAPI, StepEmails, StepBuild, StepVet}
+
+// Step is a command run in the generated project once it is rendered.
+type Step struct {
+	Name string
+	// Dir is the slash-separated directory relative to the project root.
+	Dir     string
+	Command []string
+}
+
+func (s Step) String() string {
+	command := strings.Join(s.Command, " ")
+	if s.Dir == "." {
+		return command
+	}
+	return fmt.Sprintf("cd %s && %s", s.Dir, command)
+}
+
+// Options controls how a project is written, as opposed to what it contains.
+type Options struct {
+	// Resolve decides about existing files that differ from the generated
+	// ones. Nil requires the destination to be empty.
+	Resolve ConflictResolver
+	// Steps run in order in the generated project before git is initialized.
+	Steps []Step
+	// Observe receives the progress of the generation, including the output
+	// of the steps, as it happens.
+	Observe func(Event)
+}
+
+// PostGenerateSteps returns the steps for --install and --verify, leaving out
+// the skipped ones. Verifying needs tidy modules, so it includes go mod tidy.
+func PostGenerateSteps(cfg ScaffoldConfiguration, install, verify bool, skip []string) ([]Step, error) {
+	if err := CheckStepNames(skip); err != nil {
+		return nil, err
+	}
+	pm := string(cfg.PackageManager)
+	var steps []Step
+	if install || verify {
+		steps = append(steps, Step{Name: StepTidy, Dir: "apps/api", Command: []string{"go", "mod", "tidy"}})
+	}
+	if install {
+		steps = append(steps,
+			Step{Name: StepInstall, Dir: ".", Command: []string{pm, "install"}},
+			Step{Name: StepOpenAPI, Dir: ".", Command: []string{pm, "run", "openapi:generate"}},
+			Step{Name: StepEmails, Dir: ".", Command: []string{pm, "run", "emails:generate"}},
+		)
+	}
+	if verify {
+		steps = append(steps,
+			Step{Name: StepBuild, Dir: "apps/api", Command: []string{"go", "build", "./..."}},
+			Step{Name: StepVet, Dir: "apps/api", Command: []string{"go", "vet", "./..."}},
+		)
+	}
+	kept := steps[:0]
+	for _, step := range steps {
+		if !slices.Contains(skip, step.Name) {
+			kept = append(kept, step)
+		}
+	}
+	return kept, nil
+}
+
+func CheckStepNames(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(StepNames, name) {
+			return fmt.Errorf("unknown step %q (use %s)", name, strings.Join(StepNames, "|"))
+		}
+	}
+	return nil
+}
+
+// StepError reports a failed step with the last lines it printed.
+type StepError struct {
+	Step   Step
+	Err    error
+	Output []string
+}
+
+func (e *StepError) Error() string {
+	msg := fmt.Sprintf("step %q failed: `%s`: %v", e.Step.Name, e.Step, e.Err)
+	if len(e.Output) > 0 {
+		msg += "\n" + strings.Join(e.Output, "\n")
+	}
+	return msg
+}
+
+func (e *StepError) Unwrap() error { return e.Err }
+
+// stepOutputTail is how many output lines a StepError keeps.
+const stepOutputTail = 20
+
+// runStep is replaced in tests.
+var runStep = execStep
+
+// runSteps runs the steps of opts in root and stops at the first failure.
+func runSteps(root string, opts Options) error {
+	if len(opts.Steps) == 0 {
+		return nil
+	}
+	observe := observer(opts.Observe)
+	return observe.run(PhaseSteps, func() error {
+		for _, step := range opts.Steps {
+			observe.emit(Event{Kind: EventStepStart, Phase: PhaseSteps, Step: step.Name, Command: step.String()})
+			var tail []string
+			err := runStep(root, step, func(line string) {
+				observe.emit(Event{Kind: EventStepOutput, Phase: PhaseSteps, Step: step.Name, Line: line})
+				tail = append(tail, line)
+				if len(tail) > stepOutputTail {
+					tail = tail[1:]
+				}
+			})
+			if err != nil {
+				return &StepError{Step: step, Err: err, Output: tail}
+			}
+		}
+		return nil
+	})
+}
+
+func execStep(root string, step Step, output func(line string)) error {
+	cmd := exec.Command(step.Command[0], step.Command[1:]...)
+	cmd.Dir = filepath.Join(root, filepath.FromSlash(step.Dir))
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			output(scanner.Text())
+		}
+		// Keep draining so the command never blocks on a full pipe.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	err := cmd.Wait()
+	_ = pw.Close()
+	wg.Wait()
+	return err
+}
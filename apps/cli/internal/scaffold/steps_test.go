@@ -0,0 +1,133 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func stepNames(steps []Step) []string {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.Name
+	}
+	return names
+}
+
+func TestPostGenerateSteps(t *testing.T) {
+	cfg := DefaultConfig()
+
+	steps, err := PostGenerateSteps(cfg, true, true, []string{StepEmails})
+	if err != nil {
+		t.Fatalf("steps: %v", err)
+	}
+	if got, want := stepNames(steps), []string{StepTidy, StepInstall, StepOpenAPI, StepBuild, StepVet}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := steps[1].String(); got != "bun install" {
+		t.Fatalf("unexpected install command %q", got)
+	}
+	if got := steps[0].String(); got != "cd apps/api && go mod tidy" {
+		t.Fatalf("unexpected tidy command %q", got)
+	}
+
+	steps, err = PostGenerateSteps(cfg, false, true, nil)
+	if err != nil {
+		t.Fatalf("steps: %v", err)
+	}
+	if got, want := stepNames(steps), []string{StepTidy, StepBuild, StepVet}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected verify to tidy first, got %v", got)
+	}
+
+	if steps, _ := PostGenerateSteps(cfg, false, false, nil); len(steps) != 0 {
+		t.Fatalf("expected no steps by default, got %v", stepNames(steps))
+	}
+	if _, err := PostGenerateSteps(cfg, true, false, []string{"lint"}); err == nil {
+		t.Fatalf("expected unknown step error")
+	}
+}
+
+func TestScaffoldFromFSRunsStepsBeforeGit(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "demo")
+	cfg := stageConfig(t, dest)
+	cfg.InitGit = true
+
+	var calls []string
+	runStep = func(root string, step Step, output func(string)) error {
+		if _, err := os.Stat(filepath.Join(root, "README.md")); err != nil {
+			t.Fatalf("expected %s to run in the rendered project: %v", step.Name, err)
+		}
+		calls = append(calls, step.Name)
+		output("ran " + step.Name)
+		return nil
+	}
+	initGitRepo = func(string, GitOptions) error {
+		calls = append(calls, "git")
+		return nil
+	}
+	t.Cleanup(func() { runStep, initGitRepo = execStep, InitGitRepo })
+
+	var started, lines []string
+	opts := Options{
+		Steps: []Step{{Name: StepTidy}, {Name: StepInstall}},
+		Observe: func(event Event) {
+			switch event.Kind {
+			case EventStepStart:
+				started = append(started, event.Step)
+			case EventStepOutput:
+				lines = append(lines, event.Line)
+			}
+		},
+	}
+	if err := ScaffoldFromFS(cfg, opts, stageSource(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	if want := []string{StepTidy, StepInstall, "git"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+	if want := []string{StepTidy, StepInstall}; !reflect.DeepEqual(started, want) {
+		t.Fatalf("expected started %v, got %v", want, started)
+	}
+	if want := []string{"ran tidy", "ran install"}; !reflect.DeepEqual(lines, want) {
+		t.Fatalf("expected output %v, got %v", want, lines)
+	}
+}
+
+func TestScaffoldFromFSKeepsProjectWhenStepFails(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "demo")
+
+	var ran []string
+	runStep = func(_ string, step Step, output func(string)) error {
+		ran = append(ran, step.Name)
+		if step.Name == StepInstall {
+			output("error: network unreachable")
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+	t.Cleanup(func() { runStep = execStep })
+
+	opts := Options{Steps: []Step{
+		{Name: StepTidy, Dir: "apps/api", Command: []string{"go", "mod", "tidy"}},
+		{Name: StepInstall, Dir: ".", Command: []string{"bun", "install"}},
+		{Name: StepOpenAPI, Dir: ".", Command: []string{"bun", "run", "openapi:generate"}},
+	}}
+	err := ScaffoldFromFS(stageConfig(t, dest), opts, stageSource(), nil)
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step.Name != StepInstall {
+		t.Fatalf("expected install step error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "network unreachable") || !strings.Contains(err.Error(), "`bun install`") {
+		t.Fatalf("expected the command and its output in the error, got %q", err)
+	}
+	if want := []string{StepTidy, StepInstall}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("expected steps to stop at the failure, ran %v", ran)
+	}
+	assertFileContent(t, filepath.Join(dest, "README.md"), "# demo\n")
+	assertDirEntries(t, parent, []string{"demo"})
+}
@@ -22,7 +22,7 @@ func TestScaffoldFromFS_StrictTemplates(t *testing.T) {
 		"templated.txt.tmpl": &fstest.MapFile{Data: []byte("{{PROJECT_NAME}}")},
 	}
 
-	if err := ScaffoldFromFS(cfg, true, fsys, nil); err != nil {
+	if err := ScaffoldFromFS(cfg, Options{Resolve: ConflictOverwrite.Resolver()}, fsys, nil); err != nil {
 		t.Fatalf("scaffold failed: %v", err)
 	}
 
@@ -0,0 +1,287 @@
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type valueKind int
+
+const (
+	kindBool valueKind = iota
+	kindString
+)
+
+func (k valueKind) String() string {
+	if k == kindBool {
+		return "bool"
+	}
+	return "string"
+}
+
+type conditionVar struct {
+	kind valueKind
+	get  func(cfg ScaffoldConfiguration) any
+}
+
+// conditionVars are the ScaffoldConfiguration fields templates can test in
+// {{IF ...}} blocks.
+var conditionVars = map[string]conditionVar{
+	"web":            {kindBool, func(cfg ScaffoldConfiguration) any { return cfg.IncludeWeb }},
+	"docker":         {kindBool, func(cfg ScaffoldConfiguration) any { return cfg.IncludeDocker }},
+	"git":            {kindBool, func(cfg ScaffoldConfiguration) any { return cfg.InitGit }},
+	"database":       {kindString, func(cfg ScaffoldConfiguration) any { return string(cfg.DatabaseType) }},
+	"packageManager": {kindString, func(cfg ScaffoldConfiguration) any { return string(cfg.PackageManager) }},
+	"storage":        {kindString, func(cfg ScaffoldConfiguration) any { return string(cfg.Storage.Type) }},
+	"observability":  {kindString, func(cfg ScaffoldConfiguration) any { return string(cfg.Observability) }},
+}
+
+// ConditionVariables returns the names usable in template conditions.
+func ConditionVariables() []string {
+	names := make([]string, 0, len(conditionVars))
+	for name := range conditionVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// condition is a type-checked boolean expression.
+type condition interface {
+	eval(cfg ScaffoldConfiguration) any
+	kind() valueKind
+}
+
+type literalExpr struct {
+	value any
+	k     valueKind
+}
+
+func (e literalExpr) eval(ScaffoldConfiguration) any { return e.value }
+func (e literalExpr) kind() valueKind                { return e.k }
+
+type varExpr struct {
+	name string
+	v    conditionVar
+}
+
+func (e varExpr) eval(cfg ScaffoldConfiguration) any { return e.v.get(cfg) }
+func (e varExpr) kind() valueKind                    { return e.v.kind }
+
+type notExpr struct{ operand condition }
+
+func (e notExpr) eval(cfg ScaffoldConfiguration) any { return !e.operand.eval(cfg).(bool) }
+func (e notExpr) kind() valueKind                    { return kindBool }
+
+type binaryExpr struct {
+	op          string
+	left, right condition
+}
+
+func (e binaryExpr) kind() valueKind { return kindBool }
+
+func (e binaryExpr) eval(cfg ScaffoldConfiguration) any {
+	switch e.op {
+	case "&&":
+		return e.left.eval(cfg).(bool) && e.right.eval(cfg).(bool)
+	case "||":
+		return e.left.eval(cfg).(bool) || e.right.eval(cfg).(bool)
+	case "==":
+		return e.left.eval(cfg) == e.right.eval(cfg)
+	default: // "!="
+		return e.left.eval(cfg) != e.right.eval(cfg)
+	}
+}
+
+// parseCondition parses and type-checks a condition such as
+// `storage == "s3" && !web`. Supported: identifiers, "strings", true/false,
+// ==, !=, !, &&, || and parentheses.
+func parseCondition(src string) (condition, error) {
+	tokens, err := lexCondition(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s in condition %q", tok, src)
+	}
+	if expr.kind() != kindBool {
+		return nil, fmt.Errorf("condition %q is a string, compare it with == or !=", src)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of condition"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+func lexCondition(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in condition %q", src)
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : i+1+end]})
+			i += end + 2
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i]})
+		default:
+			matched := false
+			for _, op := range []string{"&&", "||", "==", "!=", "!", "(", ")"} {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{tokOp, op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q in condition %q", c, src)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.pos] }
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) acceptOp(op string) bool {
+	if tok := p.peek(); tok.kind == tokOp && tok.value == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (condition, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (condition, error) {
+	return p.parseLogical("&&", p.parseUnary)
+}
+
+func (p *exprParser) parseLogical(op string, operand func() (condition, error)) (condition, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp(op) {
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if left.kind() != kindBool || right.kind() != kindBool {
+			return nil, fmt.Errorf("%s needs bool operands; compare strings with == or !=", op)
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (condition, error) {
+	if p.acceptOp("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if operand.kind() != kindBool {
+			return nil, fmt.Errorf("! needs a bool operand")
+		}
+		return notExpr{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (condition, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.acceptOp(op) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			if left.kind() != right.kind() {
+				return nil, fmt.Errorf("cannot compare %s with %s", left.kind(), right.kind())
+			}
+			return binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseOperand() (condition, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokOp && tok.value == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptOp(")") {
+			return nil, fmt.Errorf("expected ) but found %s", p.peek())
+		}
+		return expr, nil
+	case tok.kind == tokString:
+		return literalExpr{tok.value, kindString}, nil
+	case tok.kind == tokIdent && (tok.value == "true" || tok.value == "false"):
+		return literalExpr{tok.value == "true", kindBool}, nil
+	case tok.kind == tokIdent:
+		v, ok := conditionVars[tok.value]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q (available: %s)", tok.value, strings.Join(ConditionVariables(), ", "))
+		}
+		return varExpr{tok.value, v}, nil
+	default:
+		return nil, fmt.Errorf("expected a value but found %s", tok)
+	}
+}
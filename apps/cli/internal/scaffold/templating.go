@@ -1,38 +1,230 @@
 package scaffold
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
+// Condition blocks in *.tmpl files:
+//
+//	{{IF storage == "s3" && !web}}
+//	...
+//	{{ELSE}}
+//	...
+//	{{END}}
+//
+// Blocks nest. A tag alone on its line is removed together with that line.
 var (
-	includeWebBlockRe           = regexp.MustCompile(`(?s)\{\{IF_INCLUDE_WEB\}\}(.*?)\{\{END_IF_INCLUDE_WEB\}\}`)
-	observabilityEnabledBlockRe = regexp.MustCompile(`(?s)\{\{IF_OBSERVABILITY_ENABLED\}\}(.*?)\{\{END_IF_OBSERVABILITY_ENABLED\}\}`)
-	observabilityGrafanaBlockRe = regexp.MustCompile(`(?s)\{\{IF_OBSERVABILITY_GRAFANA\}\}(.*?)\{\{END_IF_OBSERVABILITY_GRAFANA\}\}`)
+	tagRe       = regexp.MustCompile(`\{\{(IF\s[^}]*|ELSE|END)\}\}`)
+	legacyTagRe = regexp.MustCompile(`\{\{(END_)?IF_[A-Z_]+\}\}`)
 )
 
-func ApplyTemplateConditions(input string, cfg ScaffoldConfiguration) string {
-	if cfg.IncludeWeb {
-		input = includeWebBlockRe.ReplaceAllString(input, "$1")
-	} else {
-		input = includeWebBlockRe.ReplaceAllString(input, "")
+// TemplateError points at the file and line of an invalid template.
+type TemplateError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// Template is a parsed *.tmpl file whose condition blocks can be rendered
+// for any configuration.
+type Template struct {
+	nodes []templateNode
+}
+
+type templateNode struct {
+	text      string
+	line      int // where text starts in the template
+	cond      condition
+	then      []templateNode
+	otherwise []templateNode
+}
+
+type blockFrame struct {
+	node   *templateNode
+	line   int
+	inElse bool
+	parent *[]templateNode
+}
+
+// ParseTemplate parses the condition blocks of input. file is only used in
+// error messages.
+func ParseTemplate(file, input string) (*Template, error) {
+	if loc := legacyTagRe.FindStringIndex(input); loc != nil {
+		return nil, &TemplateError{file, lineAt(input, loc[0]), fmt.Sprintf("legacy marker %s is no longer supported; use {{IF <condition>}} ... {{END}}", input[loc[0]:loc[1]])}
 	}
 
-	if cfg.Observability == ObservabilityGrafanaOSS {
-		input = observabilityEnabledBlockRe.ReplaceAllString(input, "$1")
-		input = observabilityGrafanaBlockRe.ReplaceAllString(input, "$1")
-	} else {
-		input = observabilityEnabledBlockRe.ReplaceAllString(input, "")
-		input = observabilityGrafanaBlockRe.ReplaceAllString(input, "")
+	root := &Template{}
+	current := &root.nodes
+	var stack []*blockFrame
+	pos := 0
+
+	for _, loc := range tagRe.FindAllStringSubmatchIndex(input, -1) {
+		start, end := loc[0], loc[1]
+		tag := input[loc[2]:loc[3]]
+		line := lineAt(input, start)
+
+		textEnd, next := start, end
+		if lineStart, lineEnd, ok := standaloneLine(input, start, end); ok {
+			textEnd, next = lineStart, lineEnd
+		}
+		if textEnd > pos {
+			*current = append(*current, templateNode{text: input[pos:textEnd], line: lineAt(input, pos)})
+		}
+		pos = next
+
+		switch {
+		case strings.HasPrefix(tag, "IF"):
+			expr := strings.TrimSpace(strings.TrimPrefix(tag, "IF"))
+			cond, err := parseCondition(expr)
+			if err != nil {
+				return nil, &TemplateError{file, line, err.Error()}
+			}
+			*current = append(*current, templateNode{cond: cond})
+			node := &(*current)[len(*current)-1]
+			stack = append(stack, &blockFrame{node: node, line: line, parent: current})
+			current = &node.then
+		case tag == "ELSE":
+			if len(stack) == 0 {
+				return nil, &TemplateError{file, line, "{{ELSE}} without a matching {{IF}}"}
+			}
+			frame := stack[len(stack)-1]
+			if frame.inElse {
+				return nil, &TemplateError{file, line, fmt.Sprintf("duplicate {{ELSE}} for the {{IF}} on line %d", frame.line)}
+			}
+			frame.inElse = true
+			current = &frame.node.otherwise
+		default: // END
+			if len(stack) == 0 {
+				return nil, &TemplateError{file, line, "{{END}} without a matching {{IF}}"}
+			}
+			current = stack[len(stack)-1].parent
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		frame := stack[len(stack)-1]
+		return nil, &TemplateError{file, frame.line, "{{IF}} is never closed with {{END}}"}
+	}
+	if pos < len(input) {
+		*current = append(*current, templateNode{text: input[pos:], line: lineAt(input, pos)})
 	}
+	return root, nil
+}
+
+// Render returns the template text with condition blocks resolved for cfg.
+func (t *Template) Render(cfg ScaffoldConfiguration) string {
+	var b strings.Builder
+	renderNodes(&b, t.nodes, cfg)
+	return b.String()
+}
 
-	return input
+func renderNodes(b *strings.Builder, nodes []templateNode, cfg ScaffoldConfiguration) {
+	for _, node := range nodes {
+		switch {
+		case node.cond == nil:
+			b.WriteString(node.text)
+		case node.cond.eval(cfg).(bool):
+			renderNodes(b, node.then, cfg)
+		default:
+			renderNodes(b, node.otherwise, cfg)
+		}
+	}
 }
 
+// renderLines is Render that also returns, for every line of the output, the
+// template line it comes from.
+func (t *Template) renderLines(cfg ScaffoldConfiguration) (string, []int) {
+	var b strings.Builder
+	var lines []int
+	renderNodeLines(&b, &lines, t.nodes, cfg)
+	return b.String(), lines
+}
+
+func renderNodeLines(b *strings.Builder, lines *[]int, nodes []templateNode, cfg ScaffoldConfiguration) {
+	for _, node := range nodes {
+		switch {
+		case node.cond == nil:
+			text, line := node.text, node.line
+			for text != "" {
+				end := len(text)
+				if i := strings.IndexByte(text, '\n'); i >= 0 {
+					end = i + 1
+				}
+				// Text after an inline tag continues the current output line.
+				if b.Len() == 0 || strings.HasSuffix(b.String(), "\n") {
+					*lines = append(*lines, line)
+				}
+				b.WriteString(text[:end])
+				text, line = text[end:], line+1
+			}
+		case node.cond.eval(cfg).(bool):
+			renderNodeLines(b, lines, node.then, cfg)
+		default:
+			renderNodeLines(b, lines, node.otherwise, cfg)
+		}
+	}
+}
+
+// ApplyTemplateConditions resolves the condition blocks of a template file.
+func ApplyTemplateConditions(file, input string, cfg ScaffoldConfiguration) (string, error) {
+	tmpl, err := ParseTemplate(file, input)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Render(cfg), nil
+}
+
+// HasTemplateConditions reports whether input contains condition blocks, i.e.
+// whether its rendered content depends on the configuration.
+func HasTemplateConditions(input string) bool {
+	return strings.Contains(input, "{{IF ")
+}
+
+// standaloneLine reports whether the tag at [start, end) is the only thing on
+// its line, returning the bounds of that line including its newline.
+func standaloneLine(input string, start, end int) (int, int, bool) {
+	lineStart := strings.LastIndexByte(input[:start], '\n') + 1
+	if strings.TrimLeft(input[lineStart:start], " \t") != "" {
+		return 0, 0, false
+	}
+	lineEnd := len(input)
+	if i := strings.IndexByte(input[end:], '\n'); i >= 0 {
+		lineEnd = end + i + 1
+	}
+	if strings.TrimRight(input[end:lineEnd], " \t\r\n") != "" {
+		return 0, 0, false
+	}
+	return lineStart, lineEnd, true
+}
+
+func lineAt(input string, offset int) int {
+	return strings.Count(input[:offset], "\n") + 1
+}
+
+// ReplaceTokens replaces every key of replacements in input in a single pass.
+// Where keys overlap, such as the template's project name inside its module
+// path, the longest key wins, so the output does not depend on map order.
 func ReplaceTokens(input string, replacements map[string]string) string {
-	out := input
-	for key, value := range replacements {
-		out = strings.ReplaceAll(out, key, value)
+	keys := make([]string, 0, len(replacements))
+	for key := range replacements {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	pairs := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key, replacements[key])
 	}
-	return out
+	return strings.NewReplacer(pairs...).Replace(input)
 }
@@ -1,20 +1,30 @@
 package scaffold
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestApplyTemplateConditions(t *testing.T) {
-	input := "before\n{{IF_INCLUDE_WEB}}web-only {{PROJECT_NAME}}\n{{END_IF_INCLUDE_WEB}}after\n"
+	input := "before\n{{IF web}}web-only {{PROJECT_NAME}}\n{{END}}after\n"
 
 	withWeb := DefaultConfig()
 	withWeb.IncludeWeb = true
-	got := ApplyTemplateConditions(input, withWeb)
+	got, err := ApplyTemplateConditions("README.md.tmpl", input, withWeb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if got != "before\nweb-only {{PROJECT_NAME}}\nafter\n" {
 		t.Fatalf("unexpected content with web enabled: %q", got)
 	}
 
 	withoutWeb := DefaultConfig()
 	withoutWeb.IncludeWeb = false
-	got = ApplyTemplateConditions(input, withoutWeb)
+	got, err = ApplyTemplateConditions("README.md.tmpl", input, withoutWeb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if got != "before\nafter\n" {
 		t.Fatalf("unexpected content with web disabled: %q", got)
 	}
@@ -24,8 +34,11 @@ func TestApplyTemplateConditionsThenReplaceTokens(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.IncludeWeb = true
 
-	input := "{{IF_INCLUDE_WEB}}hello {{PROJECT_NAME}}{{END_IF_INCLUDE_WEB}}"
-	content := ApplyTemplateConditions(input, cfg)
+	input := "{{IF web}}hello {{PROJECT_NAME}}{{END}}"
+	content, err := ApplyTemplateConditions("a.tmpl", input, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	content = ReplaceTokens(content, map[string]string{"{{PROJECT_NAME}}": "demo"})
 
 	if content != "hello demo" {
@@ -33,19 +46,149 @@ func TestApplyTemplateConditionsThenReplaceTokens(t *testing.T) {
 	}
 }
 
-func TestApplyTemplateConditionsObservabilityBlocks(t *testing.T) {
+func TestReplaceTokensPrefersTheLongestKey(t *testing.T) {
+	replacements := map[string]string{
+		TemplateModulePath:  "github.com/acme/demo",
+		TemplateProjectName: "demo",
+	}
+	input := "go " + TemplateModulePath + " and " + TemplateProjectName
+	for i := 0; i < 50; i++ {
+		if got := ReplaceTokens(input, replacements); got != "go github.com/acme/demo and demo" {
+			t.Fatalf("unexpected replacement: %q", got)
+		}
+	}
+}
+
+func TestRenderProjectIsReproducible(t *testing.T) {
+	source, err := EmbeddedTemplate()
+	if err != nil {
+		t.Fatalf("embedded template: %v", err)
+	}
 	cfg := DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	first, err := RenderProject(cfg, source)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if readme := string(first["README.md"]); strings.Contains(readme, "jeheskielSunloy77") {
+		t.Fatalf("expected the template module path to be replaced in README.md:\n%s", readme)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := RenderProject(cfg, source)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		for path, content := range first {
+			if string(again[path]) != string(content) {
+				t.Fatalf("render %d of %s differs from the first", i+2, path)
+			}
+		}
+	}
+}
+
+func TestApplyTemplateConditionsElseAndNesting(t *testing.T) {
+	input := strings.Join([]string{
+		"start",
+		`{{IF storage == "s3" && !web}}`,
+		"s3 api only",
+		`  {{IF observability != "none"}}`,
+		"  traced",
+		"  {{END}}",
+		"{{ELSE}}",
+		"fallback",
+		"{{END}}",
+		"end",
+		"",
+	}, "\n")
+
+	cases := []struct {
+		name  string
+		setup func(cfg *ScaffoldConfiguration)
+		want  string
+	}{
+		{"else branch", func(cfg *ScaffoldConfiguration) {}, "start\nfallback\nend\n"},
+		{"s3 without web", func(cfg *ScaffoldConfiguration) {
+			cfg.Storage.Type = StorageS3
+			cfg.IncludeWeb = false
+		}, "start\ns3 api only\nend\n"},
+		{"nested block", func(cfg *ScaffoldConfiguration) {
+			cfg.Storage.Type = StorageS3
+			cfg.IncludeWeb = false
+			cfg.Observability = ObservabilityGrafanaOSS
+		}, "start\ns3 api only\n  traced\nend\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			c.setup(&cfg)
+			got, err := ApplyTemplateConditions("a.tmpl", input, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Fatalf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateConditionsOperators(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.IncludeWeb = false
 	cfg.Observability = ObservabilityGrafanaOSS
 
-	input := "{{IF_OBSERVABILITY_ENABLED}}enabled{{END_IF_OBSERVABILITY_ENABLED}}{{IF_OBSERVABILITY_GRAFANA}} grafana{{END_IF_OBSERVABILITY_GRAFANA}}"
-	content := ApplyTemplateConditions(input, cfg)
-	if content != "enabled grafana" {
-		t.Fatalf("unexpected rendered content: %q", content)
+	cases := map[string]bool{
+		`web`:                                    false,
+		`!web`:                                   true,
+		`web || docker`:                          true,
+		`!(web || docker)`:                       false,
+		`observability == "grafana-oss"`:         true,
+		`observability != "grafana-oss" || !web`: true,
+		`web == false && database == "postgres"`: true,
+		`storage == "s3" || packageManager == "bun"`: true,
+	}
+	for expr, want := range cases {
+		got, err := ApplyTemplateConditions("a.tmpl", "{{IF "+expr+"}}yes{{ELSE}}no{{END}}", cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", expr, err)
+		}
+		if (got == "yes") != want {
+			t.Fatalf("%s: expected %v, got %q", expr, want, got)
+		}
 	}
+}
 
-	cfg.Observability = ObservabilityNone
-	content = ApplyTemplateConditions(input, cfg)
-	if content != "" {
-		t.Fatalf("expected observability blocks to be removed, got %q", content)
+func TestApplyTemplateConditionsErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		line  int
+		want  string
+	}{
+		{"unclosed", "a\n{{IF web}}\nb\n", 2, "never closed"},
+		{"stray end", "a\nb\n{{END}}\n", 3, "{{END}} without a matching {{IF}}"},
+		{"stray else", "{{ELSE}}\n", 1, "{{ELSE}} without a matching {{IF}}"},
+		{"duplicate else", "{{IF web}}\n{{ELSE}}\n{{ELSE}}\n{{END}}\n", 3, "duplicate {{ELSE}}"},
+		{"unknown variable", "\n\n{{IF webb}}x{{END}}", 3, `unknown variable "webb"`},
+		{"string as bool", `{{IF storage}}x{{END}}`, 1, "compare it with == or !="},
+		{"type mismatch", `{{IF web == "true"}}x{{END}}`, 1, "cannot compare bool with string"},
+		{"bad syntax", `{{IF web &&}}x{{END}}`, 1, "expected a value"},
+		{"legacy marker", "x\n{{IF_INCLUDE_WEB}}x{{END_IF_INCLUDE_WEB}}", 2, "legacy marker {{IF_INCLUDE_WEB}}"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ApplyTemplateConditions("docs/a.md.tmpl", c.input, DefaultConfig())
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("expected TemplateError, got %v", err)
+			}
+			if templateErr.File != "docs/a.md.tmpl" || templateErr.Line != c.line {
+				t.Fatalf("expected docs/a.md.tmpl:%d, got %s:%d", c.line, templateErr.File, templateErr.Line)
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("expected error containing %q, got %v", c.want, err)
+			}
+		})
 	}
 }
@@ -0,0 +1,148 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/validate"
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+type ConfigError struct {
+	Errors []FieldError
+}
+
+func (e *ConfigError) Error() string {
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("%s: %s", e.Errors[0].Field, e.Errors[0].Message)
+	}
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, "invalid configuration:")
+	for _, fe := range e.Errors {
+		lines = append(lines, fmt.Sprintf("  - %s: %s", fe.Field, fe.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ConfigError) add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateConfig checks cfg and reports every invalid field at once. Field
+// names match the keys used in config files.
+func ValidateConfig(cfg ScaffoldConfiguration) error {
+	errs := &ConfigError{}
+
+	if err := validate.ProjectName(cfg.ProjectName); err != nil {
+		errs.add("name", "%v", err)
+	}
+	if err := validate.ModulePath(cfg.ModulePath); err != nil {
+		errs.add("module", "%v", err)
+	}
+
+	switch cfg.PackageManager {
+	case PackageBun, PackageNPM, PackagePNPM, PackageYarn:
+	default:
+		errs.add("packageManager", "unsupported package manager %q (supported: bun, npm, pnpm, yarn)", cfg.PackageManager)
+	}
+
+	switch cfg.Observability {
+	case ObservabilityNone, ObservabilityGrafanaOSS, ObservabilityOTLP:
+	default:
+		errs.add("observability", "unsupported observability stack: %s", cfg.Observability)
+	}
+
+	switch cfg.DatabaseType {
+	case DatabasePostgres, DatabaseMySQL:
+	default:
+		errs.add("database.type", "unsupported database %q (supported: postgres, mysql)", cfg.DatabaseType)
+	}
+	checkPort(errs, "database.port", cfg.DBConnection.Port)
+	checkPort(errs, "ports.api", cfg.APIPort)
+	checkPort(errs, "ports.web", cfg.WebPort)
+	if cfg.APIPort != "" && cfg.APIPort == cfg.WebPort {
+		errs.add("ports.web", "must differ from ports.api (%s)", cfg.APIPort)
+	}
+
+	if cfg.Git.Branch != "" {
+		if err := validate.GitBranch(cfg.Git.Branch); err != nil {
+			errs.add("gitOptions.branch", "%v", err)
+		}
+	}
+	if cfg.Git.AuthorEmail != "" {
+		if err := validate.Email(cfg.Git.AuthorEmail); err != nil {
+			errs.add("gitOptions.authorEmail", "%v", err)
+		}
+	}
+
+	switch cfg.Metadata.License {
+	case LicenseMIT, LicenseApache, LicenseBSD3, LicenseProprietary:
+	default:
+		errs.add("project.license", "unsupported license %q (supported: MIT, Apache-2.0, BSD-3-Clause, proprietary)", cfg.Metadata.License)
+	}
+	if err := validate.MetadataText(cfg.Metadata.Description); err != nil {
+		errs.add("project.description", "%v", err)
+	}
+	if err := validate.MetadataText(cfg.Metadata.Author); err != nil {
+		errs.add("project.author", "%v", err)
+	}
+	if cfg.Metadata.Homepage != "" {
+		if err := validate.Homepage(cfg.Metadata.Homepage); err != nil {
+			errs.add("project.homepage", "%v", err)
+		}
+	}
+
+	switch cfg.Storage.Type {
+	case StorageLocal:
+		if cfg.Storage.Local == nil || strings.TrimSpace(cfg.Storage.Local.Path) == "" {
+			errs.add("storage.local.path", "is required when storage type is local")
+		}
+	case StorageS3:
+		if missing := missingS3Fields(cfg.Storage.S3); len(missing) > 0 {
+			errs.add("storage.s3", "s3 storage selected: all s3 connection details are required (missing: %s)", strings.Join(missing, ", "))
+		}
+	default:
+		errs.add("storage.type", "unsupported storage type %q (supported: local, s3)", cfg.Storage.Type)
+	}
+
+	if len(errs.Errors) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkPort reports a port that is set but not a valid TCP port.
+func checkPort(errs *ConfigError, field, value string) {
+	if value == "" {
+		return
+	}
+	if validate.Port(value) != nil {
+		errs.add(field, "must be a number between 1 and 65535, got %q", value)
+	}
+}
+
+func missingS3Fields(s3 *S3Config) []string {
+	if s3 == nil {
+		return []string{"endpoint", "region", "bucket", "accessKey", "secretKey"}
+	}
+	var missing []string
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"endpoint", s3.Endpoint},
+		{"region", s3.Region},
+		{"bucket", s3.Bucket},
+		{"accessKey", s3.AccessKey},
+		{"secretKey", s3.SecretKey},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
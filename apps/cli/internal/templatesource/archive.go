@@ -0,0 +1,195 @@
+package templatesource
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isArchive(location string) bool {
+	lower := strings.ToLower(location)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".zip")
+}
+
+// extractArchive unpacks a template pack into the cache, keyed by the hash of
+// the archive so a changed pack is extracted again.
+func extractArchive(location, cacheDir string) (string, error) {
+	sum, err := fileHash(location)
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(cacheDir, "archive", sum)
+	err = populate(target, func(dir string) error {
+		if strings.HasSuffix(strings.ToLower(location), ".zip") {
+			return extractZip(location, dir)
+		}
+		f, err := os.Open(location)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return extractTar(gz, dir)
+	})
+	if err != nil {
+		return "", err
+	}
+	return unwrapSingleDir(target)
+}
+
+// populate fills target once: fill writes into a temporary sibling directory
+// which is then renamed into place, so an interrupted extraction never leaves
+// a half-filled cache entry behind.
+func populate(target string, fill func(dir string) error) error {
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(target), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if err := fill(tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.RemoveAll(tmp)
+		// Another process may have populated the entry in the meantime.
+		if _, statErr := os.Stat(target); statErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			target, err := safeJoin(dir, header.Name)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeEntry(dir, header.Name, tr); err != nil {
+				return err
+			}
+		default:
+			// Links and special files are not part of a template.
+		}
+	}
+}
+
+func extractZip(location, dir string) error {
+	zr, err := zip.OpenReader(location)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			target, err := safeJoin(dir, file.Name)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = writeEntry(dir, file.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeEntry(dir, name string, r io.Reader) error {
+	target, err := safeJoin(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// safeJoin rejects entries that would escape dir.
+func safeJoin(dir, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(strings.ReplaceAll(name, `\`, "/")))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q escapes the extraction directory", name)
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+// unwrapSingleDir returns the only entry of dir when it is a directory, as in
+// packs created with `tar czf template.tar.gz my-template/`.
+func unwrapSingleDir(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
+func fileHash(location string) (string, error) {
+	f, err := os.Open(location)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,51 @@
+package templatesource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// checkoutGit exports the tree of repo at ref into the cache, keyed by the
+// resolved commit, and returns the commit and the export directory.
+func checkoutGit(repo, ref, cacheDir string) (string, string, error) {
+	repo, err := filepath.Abs(repo)
+	if err != nil {
+		return "", "", err
+	}
+	out, err := runGit(repo, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", "", fmt.Errorf("unknown git ref %q in %s", ref, repo)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	target := filepath.Join(cacheDir, "git", commit)
+	err = populate(target, func(dir string) error {
+		archive, err := runGit(repo, "archive", "--format=tar", commit)
+		if err != nil {
+			return err
+		}
+		return extractTar(bytes.NewReader(archive), dir)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return commit, target, nil
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
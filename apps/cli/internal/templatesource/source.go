@@ -0,0 +1,173 @@
+package templatesource
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/templates"
+)
+
+type Kind string
+
+const (
+	KindEmbedded Kind = "embedded"
+	KindDir      Kind = "dir"
+	KindArchive  Kind = "archive"
+	KindGit      Kind = "git"
+)
+
+type Source struct {
+	FS   fs.FS
+	Kind Kind
+	// Spec is the --template value the source was resolved from.
+	Spec string
+	// Revision is the resolved git commit for git sources.
+	Revision string
+}
+
+// Describe returns a short human readable description of the source.
+func (s Source) Describe() string {
+	switch s.Kind {
+	case KindEmbedded:
+		return "embedded"
+	case KindGit:
+		return fmt.Sprintf("%s (git %s)", s.Spec, shortRevision(s.Revision))
+	default:
+		return s.Spec
+	}
+}
+
+type Options struct {
+	// CacheDir overrides where archives and git checkouts are extracted.
+	// Defaults to <user cache dir>/gokickstart/templates.
+	CacheDir string
+}
+
+// Resolve turns a template spec into a Source. Supported specs:
+//
+//	""                          the template embedded in the CLI
+//	./my-template               a local directory
+//	./my-template.tar.gz        a .tar.gz/.tgz or .zip pack
+//	./fork#v1.2.0               a local git repository at a ref
+//
+// Any of these may select a sub directory with //, for example
+// ../go-kickstart//apps/cli/templates/monorepo#main.
+func Resolve(spec string, opts Options) (Source, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		sub, err := fs.Sub(templates.MonorepoFS, "monorepo")
+		return Source{FS: sub, Kind: KindEmbedded}, err
+	}
+
+	location, subdir, ref, hasRef := parseSpec(spec)
+
+	cacheDir, err := cacheRoot(opts.CacheDir)
+	if err != nil {
+		return Source{}, err
+	}
+
+	src := Source{Spec: spec}
+	var root string
+	switch {
+	case hasRef:
+		if ref == "" {
+			return Source{}, fmt.Errorf("template %q: empty git ref after #", spec)
+		}
+		src.Kind = KindGit
+		src.Revision, root, err = checkoutGit(location, ref, cacheDir)
+	case isArchive(location):
+		src.Kind = KindArchive
+		root, err = extractArchive(location, cacheDir)
+	default:
+		src.Kind = KindDir
+		root, err = localDir(location)
+	}
+	if err != nil {
+		return Source{}, fmt.Errorf("template %q: %w", spec, err)
+	}
+
+	if subdir != "" {
+		root = filepath.Join(root, filepath.FromSlash(subdir))
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return Source{}, fmt.Errorf("template %q: %w", spec, err)
+	}
+	if !info.IsDir() {
+		return Source{}, fmt.Errorf("template %q: %s is not a directory", spec, root)
+	}
+	src.FS = os.DirFS(root)
+	return src, nil
+}
+
+// Absolute rewrites the location of spec as an absolute path so the spec can
+// be recorded and resolved again from another working directory.
+func Absolute(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", nil
+	}
+	location, subdir, ref, hasRef := parseSpec(spec)
+	abs, err := filepath.Abs(location)
+	if err != nil {
+		return "", err
+	}
+	if subdir != "" {
+		abs += "//" + subdir
+	}
+	if hasRef {
+		abs += "#" + ref
+	}
+	return abs, nil
+}
+
+// parseSpec splits location//subdir#ref. The sub directory may also follow
+// the ref (location#ref//subdir); git refs never contain "//".
+func parseSpec(spec string) (location, subdir, ref string, hasRef bool) {
+	location, ref, hasRef = strings.Cut(spec, "#")
+	location, subdir, _ = strings.Cut(location, "//")
+	if r, s, ok := strings.Cut(ref, "//"); ok {
+		ref, subdir = r, s
+	}
+	subdir = path.Clean("/" + subdir)[1:]
+	return location, subdir, ref, hasRef
+}
+
+// DefaultCacheDir is where extracted templates are kept between runs.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gokickstart", "templates"), nil
+}
+
+func cacheRoot(override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	return DefaultCacheDir()
+}
+
+func localDir(location string) (string, error) {
+	info, err := os.Stat(location)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", errors.New("not a directory, .tar.gz/.tgz/.zip pack or git repository (use path#ref)")
+	}
+	return filepath.Abs(location)
+}
+
+func shortRevision(rev string) string {
+	if len(rev) > 12 {
+		return rev[:12]
+	}
+	return rev
+}
@@ -0,0 +1,223 @@
+package templatesource
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var templateFiles = map[string]string{
+	"README.md.tmpl":        "# {{PROJECT_NAME}}\n",
+	"apps/api/.env.example": "PORT=8080\n",
+}
+
+func TestResolveEmbedded(t *testing.T) {
+	src, err := Resolve("", Options{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.Kind != KindEmbedded {
+		t.Fatalf("expected embedded source, got %s", src.Kind)
+	}
+	if _, err := fs.Stat(src.FS, "apps/api/go.mod.tmpl"); err != nil {
+		t.Fatalf("expected embedded template files: %v", err)
+	}
+}
+
+func TestResolveDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, filepath.Join(dir, "templates", "monorepo"), templateFiles)
+
+	src, err := Resolve(dir+"//templates/monorepo", Options{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.Kind != KindDir {
+		t.Fatalf("expected dir source, got %s", src.Kind)
+	}
+	assertFiles(t, src.FS, templateFiles)
+
+	if _, err := Resolve(filepath.Join(dir, "missing"), Options{CacheDir: t.TempDir()}); err == nil {
+		t.Fatalf("expected error for a missing directory")
+	}
+}
+
+func TestResolveTarGz(t *testing.T) {
+	pack := filepath.Join(t.TempDir(), "template.tar.gz")
+	f, err := os.Create(pack)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range templateFiles {
+		// Packs usually wrap the template in a single top-level directory.
+		header := &tar.Header{Name: "my-template/" + name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+	f.Close()
+
+	cache := t.TempDir()
+	src, err := Resolve(pack, Options{CacheDir: cache})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.Kind != KindArchive {
+		t.Fatalf("expected archive source, got %s", src.Kind)
+	}
+	assertFiles(t, src.FS, templateFiles)
+
+	// A second resolve is served from the cache.
+	if _, err := Resolve(pack, Options{CacheDir: cache}); err != nil {
+		t.Fatalf("unexpected error from cache: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(cache, "archive"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %v (%v)", entries, err)
+	}
+}
+
+func TestResolveZip(t *testing.T) {
+	pack := writeZip(t, templateFiles)
+	src, err := Resolve(pack, Options{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertFiles(t, src.FS, templateFiles)
+}
+
+func TestResolveRejectsEscapingArchive(t *testing.T) {
+	pack := writeZip(t, map[string]string{"../evil.txt": "x"})
+	_, err := Resolve(pack, Options{CacheDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "escapes the extraction directory") {
+		t.Fatalf("expected escaping entry to be rejected, got %v", err)
+	}
+}
+
+func TestResolveGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	git("init", "-q")
+	writeFiles(t, repo, templateFiles)
+	git("add", "-A")
+	git("commit", "-q", "-m", "v1")
+	git("tag", "v1")
+	writeFiles(t, repo, map[string]string{"README.md.tmpl": "# changed\n"})
+	git("commit", "-q", "-am", "v2")
+
+	src, err := Resolve(repo+"#v1", Options{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.Kind != KindGit || len(src.Revision) != 40 {
+		t.Fatalf("expected git source with a commit, got %s %q", src.Kind, src.Revision)
+	}
+	assertFiles(t, src.FS, templateFiles)
+
+	if _, err := Resolve(repo+"#does-not-exist", Options{CacheDir: t.TempDir()}); err == nil {
+		t.Fatalf("expected error for an unknown ref")
+	}
+}
+
+func TestAbsolute(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	cases := map[string]string{
+		"":                       "",
+		"tpl":                    filepath.Join(wd, "tpl"),
+		"fork//templates#v1.2.0": filepath.Join(wd, "fork") + "//templates#v1.2.0",
+		"pack.tar.gz":            filepath.Join(wd, "pack.tar.gz"),
+		"/abs/fork#main":         "/abs/fork#main",
+	}
+	for spec, want := range cases {
+		got, err := Absolute(spec)
+		if err != nil {
+			t.Fatalf("Absolute(%q): %v", spec, err)
+		}
+		if got != want {
+			t.Fatalf("Absolute(%q) = %q, want %q", spec, got, want)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func writeZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	pack := filepath.Join(t.TempDir(), "template.zip")
+	f, err := os.Create(pack)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return pack
+}
+
+func assertFiles(t *testing.T, fsys fs.FS, files map[string]string) {
+	t.Helper()
+	for name, want := range files {
+		got, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
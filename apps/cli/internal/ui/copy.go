@@ -8,12 +8,26 @@ const (
 	FlowDescription = "Choose how hard you want to min-max this setup."
 	FlowBasicLabel  = "🚀 Speedrun (defaults)"
 	FlowAdvLabel    = "🛠️  Full control (advanced)"
+	FlowPresetLabel = "📦 Preset: %s"
 
 	ProjectNameTitle       = "Project name"
 	ProjectNameDescription = "Name your repo. Keep it clean, future-you is watching."
 	ModuleTitle            = "Go module path"
 	ModuleDescription      = "Package identity unlocked."
 
+	DescriptionTitle        = "Description"
+	DescriptionDesc         = "One line for package.json, the README and image labels. Leave empty for a generic one."
+	AuthorTitle             = "Author"
+	AuthorDesc              = "Copyright holder, e.g. Acme Inc. or Jane Doe <jane@example.com>. Empty means \"The <name> authors\"."
+	HomepageTitle           = "Homepage"
+	HomepageDesc            = "Project URL. Leave empty to use https://<module path>."
+	LicenseTitle            = "License"
+	LicenseDesc             = "Written to LICENSE and every package.json."
+	LicenseMITLabel         = "🕊️  MIT"
+	LicenseApacheLabel      = "🏛️  Apache-2.0"
+	LicenseBSD3Label        = "📜 BSD-3-Clause"
+	LicenseProprietaryLabel = "🔒 Proprietary (all rights reserved)"
+
 	DestinationTitle       = "Base destination path"
 	DestinationDescription = "Where the repo spawns. Final path is <base>/<project-name>."
 
@@ -21,21 +35,43 @@ const (
 	IncludeWebDescription = "Ship full-stack mode with Vite + React."
 	IncludeDockerTitle    = "Include Docker Compose"
 	IncludeDockerDesc     = "One command stack spin-up energy."
+	APIPortTitle          = "API port"
+	APIPortDesc           = "Where the API listens locally. The web app and CORS follow it."
+	WebPortTitle          = "Web port"
+	WebPortDesc           = "Where the web app listens locally."
 	IncludeGitTitle       = "Initialize git repository"
 	IncludeGitDescription = "Start with commit history from frame 1."
+	GitBranchTitle        = "Initial branch"
+	GitBranchDesc         = "Leave empty for git's init.defaultBranch."
+	GitRemoteTitle        = "Remote origin"
+	GitRemoteDesc         = "Optional URL to add as origin, e.g. git@github.com:acme/app.git."
+	GitIdentityTitle      = "Who signs the first commit?"
+	GitIdentityDesc       = "git has no user.name/user.email configured here. Leave both empty to stage the files without committing."
+	GitAuthorNameTitle    = "Author name"
+	GitAuthorEmailTitle   = "Author email"
+	PackageManagerTitle   = "Package manager"
+	PackageManagerDesc    = "Who wrangles node_modules in the workspace."
+	PackageBunLabel       = "🥟 Bun"
+	PackageNPMLabel       = "📦 npm"
+	PackagePNPMLabel      = "⚡ pnpm"
+	PackageYarnLabel      = "🧶 Yarn"
 
-	DBHostTitle = "Postgres host"
-	DBHostDesc  = "Database coordinates. localhost is a classic."
-	DBPortTitle = "Postgres port"
-	DBPortDesc  = "Usually 5432 unless your setup is spicy."
-	DBUserTitle = "Postgres user"
-	DBUserDesc  = "The DB hero account."
-	DBPassTitle = "Postgres password"
-	DBPassDesc  = "Type carefully. This one bites."
-	DBNameTitle = "Postgres database"
-	DBNameDesc  = "Primary schema arena."
-	DBSSLTitle  = "Postgres SSL mode"
-	DBSSLDesc   = "dev tip: disable locally, require in prod."
+	DBTypeTitle     = "Database"
+	DBTypeDesc      = "Pick the engine your data lives in."
+	DBPostgresLabel = "🐘 PostgreSQL"
+	DBMySQLLabel    = "🐬 MySQL"
+	DBHostTitle     = "Database host"
+	DBHostDesc      = "Database coordinates. localhost is a classic."
+	DBPortTitle     = "Database port"
+	DBPortDesc      = "5432 for Postgres, 3306 for MySQL, unless your setup is spicy."
+	DBUserTitle     = "Database user"
+	DBUserDesc      = "The DB hero account."
+	DBPassTitle     = "Database password"
+	DBPassDesc      = "Type carefully. This one bites."
+	DBNameTitle     = "Database name"
+	DBNameDesc      = "Primary schema arena."
+	DBSSLTitle      = "Database SSL mode"
+	DBSSLDesc       = "disable | require | verify-full. dev tip: disable locally, require in prod."
 
 	StorageTitle                 = "Storage provider"
 	StorageDescription           = "Pick where files live after upload."
@@ -52,17 +88,34 @@ const (
 	ObservabilityDescription     = "Pick the generated observability story for this app."
 	ObservabilityNoneLabel       = "🪶 No bundled stack"
 	ObservabilityGrafanaOSSLabel = "📊 Grafana OSS"
+	ObservabilityOTLPLabel       = "📡 OTLP only (bring your own collector)"
+
+	ReviewTitle            = "Final boss check"
+	ReviewActionTitle      = "What do you think?"
+	ReviewGenerateLabel    = "🚀 Looks good, lets get busy!"
+	ReviewEditLabel        = "↩️  Start over"
+	ReviewEditSectionLabel = "✏️  Tweak %s"
+	ReviewCancelLabel      = "🛑 Abort mission"
+	ReviewSummaryHeading   = "Config snapshot"
 
-	ReviewTitle          = "Final boss check"
-	ReviewActionTitle    = "What do you think?"
-	ReviewGenerateLabel  = "🚀 Looks good, lets get busy!"
-	ReviewEditLabel      = "↩️  Changed my mind"
-	ReviewCancelLabel    = "🛑 Abort mission"
-	ReviewSummaryHeading = "Config snapshot"
+	PhaseRenderLabel = "Rendering templates..."
+	PhaseEnvLabel    = "Writing .env files..."
+	PhaseStepsLabel  = "Running post-generation steps..."
+	PhaseGitLabel    = "Initializing the git repository..."
+	PhasePlaceLabel  = "Moving the project into place..."
 
-	OverwriteTitle = "Destination is not empty. Continue anyway?"
-	OverwriteAff   = "YOLO"
-	OverwriteNeg   = "Nope"
+	ConflictPolicyTitle       = "Destination is not empty."
+	ConflictPolicyDescription = "Existing files that differ from the template need a decision."
+	ConflictPromptLabel       = "🔍 Ask me file by file (with diffs)"
+	ConflictSkipLabel         = "🛡️  Keep my files"
+	ConflictBackupLabel       = "💾 Overwrite, keeping <file>.bak copies"
+	ConflictOverwriteLabel    = "🔥 YOLO, overwrite everything"
+	ConflictCancelLabel       = "🛑 Nope"
+	ConflictFileTitle         = "Conflict"
+	ConflictActionTitle       = "Which version wins?"
+	ConflictKeepLabel         = "Keep mine"
+	ConflictReplaceLabel      = "Take the template's"
+	ConflictWriteNewLabel     = "Keep mine, write the template's to %s"
 )
 
 func ContributionLine() string {
@@ -14,7 +14,8 @@ func TestCopyConstantsAreNonEmpty(t *testing.T) {
 		ReviewTitle,
 		ReviewActionTitle,
 		ReviewSummaryHeading,
-		OverwriteTitle,
+		ConflictPolicyTitle,
+		ConflictFileTitle,
 		ContributionLine(),
 	}
 
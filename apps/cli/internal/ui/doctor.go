@@ -0,0 +1,33 @@
+package ui
+
+import "fmt"
+
+type DoctorCheck struct {
+	Group  string
+	Name   string
+	Status string
+	Detail string
+	Fix    string
+}
+
+var doctorMarkers = map[string]string{
+	"pass": "✅",
+	"warn": "⚠️ ",
+	"fail": "❌",
+}
+
+func PrintDoctor(title string, checks []DoctorCheck, summary string) {
+	fmt.Printf("\n%s\n", SectionTitleStyle().Render(title))
+	group := ""
+	for _, check := range checks {
+		if check.Group != group {
+			group = check.Group
+			fmt.Println(HintStyle().Render(group + ":"))
+		}
+		fmt.Printf("   %s %s %s\n", doctorMarkers[check.Status], TitleStyle.Render(check.Name), MutedStyle.Render(check.Detail))
+		if check.Fix != "" {
+			fmt.Printf("      %s %s\n", HintStyle().Render("fix:"), check.Fix)
+		}
+	}
+	fmt.Printf("\n%s\n", summary)
+}
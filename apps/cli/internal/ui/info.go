@@ -0,0 +1,29 @@
+package ui
+
+import "fmt"
+
+type InfoRow struct {
+	Label string
+	Value string
+}
+
+func PrintInfo(title string, rows []InfoRow, modified []string, missing []string) {
+	fmt.Printf("\n%s\n", SectionTitleStyle().Render(title))
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row.Label))
+	}
+	for _, row := range rows {
+		fmt.Printf("   %s %s\n", HintStyle().Render(fmt.Sprintf("%-*s", width+1, row.Label+":")), row.Value)
+	}
+	if len(modified) == 0 && len(missing) == 0 {
+		return
+	}
+	fmt.Println(HintStyle().Render("Changed since generation:"))
+	for _, path := range modified {
+		fmt.Printf("   %s %s\n", HintStyle().Render("~"), path)
+	}
+	for _, path := range missing {
+		fmt.Printf("   %s %s\n", HintStyle().Render("-"), path)
+	}
+}
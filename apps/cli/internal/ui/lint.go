@@ -0,0 +1,20 @@
+package ui
+
+import "fmt"
+
+type LintIssue struct {
+	Location    string
+	Message     string
+	Combination string
+}
+
+func PrintLint(title string, issues []LintIssue, summary string) {
+	fmt.Printf("\n%s\n", SectionTitleStyle().Render(title))
+	for _, issue := range issues {
+		fmt.Printf("   ❌ %s %s\n", TitleStyle.Render(issue.Location), issue.Message)
+		if issue.Combination != "" {
+			fmt.Printf("      %s %s\n", HintStyle().Render("with:"), MutedStyle.Render(issue.Combination))
+		}
+	}
+	fmt.Printf("\n%s\n", summary)
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type PlanEntry struct {
+	Path  string
+	Notes []string
+}
+
+type planNode struct {
+	name     string
+	notes    []string
+	children map[string]*planNode
+}
+
+func PrintPlan(root string, entries []PlanEntry, excluded []string) {
+	tree := &planNode{children: map[string]*planNode{}}
+	for _, entry := range entries {
+		node := tree
+		for _, part := range strings.Split(entry.Path, "/") {
+			child, ok := node.children[part]
+			if !ok {
+				child = &planNode{name: part, children: map[string]*planNode{}}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.notes = entry.Notes
+	}
+
+	fmt.Printf("\n%s\n", SectionTitleStyle().Render(fmt.Sprintf("📝 Dry run: %d files would be written", len(entries))))
+	fmt.Println(BannerStyle().Render(root))
+	printPlanChildren(tree, "")
+	if len(excluded) > 0 {
+		fmt.Println(HintStyle().Render("Excluded by your options:"))
+		for _, path := range excluded {
+			fmt.Printf("   - %s\n", path)
+		}
+	}
+	fmt.Println(HintStyle().Render("Nothing was written. Run again without --dry-run to generate."))
+}
+
+func printPlanChildren(node *planNode, prefix string) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		child := node.children[name]
+		connector, indent := "├── ", "│   "
+		if i == len(names)-1 {
+			connector, indent = "└── ", "    "
+		}
+		label := child.name
+		if len(child.children) > 0 {
+			label += "/"
+		}
+		if len(child.notes) > 0 {
+			label += " " + HintStyle().Render("["+strings.Join(child.notes, "; ")+"]")
+		}
+		fmt.Printf("%s%s%s\n", prefix, connector, label)
+		printPlanChildren(child, prefix+indent)
+	}
+}
+
+var (
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	diffRemoveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// FormatDiff colors a unified diff and cuts it to maxLines lines.
+func FormatDiff(diff string, maxLines int) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	hidden := 0
+	if maxLines > 0 && len(lines) > maxLines {
+		hidden = len(lines) - maxLines
+		lines = lines[:maxLines]
+	}
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = MutedStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffRemoveStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = AccentStyle.Render(line)
+		}
+	}
+	if hidden > 0 {
+		lines = append(lines, MutedStyle.Render(fmt.Sprintf("… %d more lines", hidden)))
+	}
+	return strings.Join(lines, "\n")
+}
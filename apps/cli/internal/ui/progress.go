@@ -2,28 +2,55 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// ProgressUpdate is what a task running under RunWithProgress reports. A
+// non-empty Status replaces the spinner message and clears the counts and
+// output; a non-empty Line is added to the live output below it. Done counts
+// files, out of Total when it is known.
+type ProgressUpdate struct {
+	Status string
+	Line   string
+	Done   int
+	Total  int
+}
+
+// Progress updates a running spinner.
+type Progress func(ProgressUpdate)
+
+const (
+	// progressTail is how many output lines the spinner shows.
+	progressTail = 6
+	// progressBarWidth is the width of the bar in cells.
+	progressBarWidth = 30
+)
+
 type spinnerModel struct {
 	spinner spinner.Model
 	message string
+	lines   []string
+	count   int
+	total   int
 	err     error
 	done    <-chan error
 }
 
 type spinnerDoneMsg struct{ err error }
 
-type spinnerTickMsg struct{}
+type spinnerProgressMsg ProgressUpdate
 
 func RunWithSpinner(message string, fn func() error) error {
-	done := make(chan error, 1)
-	go func() {
-		done <- fn()
-	}()
+	return RunWithProgress(message, func(Progress) error { return fn() })
+}
 
+// RunWithProgress shows a spinner while fn runs and lets fn report what it
+// is doing.
+func RunWithProgress(message string, fn func(progress Progress) error) error {
+	done := make(chan error, 1)
 	m := spinnerModel{
 		spinner: spinner.New(),
 		message: message,
@@ -31,6 +58,11 @@ func RunWithSpinner(message string, fn func() error) error {
 	}
 
 	p := tea.NewProgram(m)
+	go func() {
+		done <- fn(func(update ProgressUpdate) {
+			p.Send(spinnerProgressMsg(update))
+		})
+	}()
 	final, err := p.Run()
 	if err != nil {
 		return err
@@ -49,7 +81,24 @@ func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case spinnerDoneMsg:
 		m.err = msg.err
+		m.lines = nil
 		return m, tea.Quit
+	case spinnerProgressMsg:
+		if msg.Status != "" {
+			m.message = msg.Status
+			m.lines = nil
+			m.count, m.total = 0, 0
+		}
+		if msg.Done > 0 {
+			m.count, m.total = msg.Done, msg.Total
+		}
+		if msg.Line != "" {
+			m.lines = append(m.lines, msg.Line)
+			if len(m.lines) > progressTail {
+				m.lines = m.lines[len(m.lines)-progressTail:]
+			}
+		}
+		return m, nil
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
 			m.err = fmt.Errorf("cancelled")
@@ -62,7 +111,18 @@ func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m spinnerModel) View() string {
-	return fmt.Sprintf("%s %s", m.spinner.View(), m.message)
+	view := fmt.Sprintf("%s %s", m.spinner.View(), m.message)
+	if m.total > 0 {
+		filled := min(m.count, m.total) * progressBarWidth / m.total
+		bar := AccentStyle.Render(strings.Repeat("█", filled)) + MutedStyle.Render(strings.Repeat("░", progressBarWidth-filled))
+		view += fmt.Sprintf("\n  %s %d/%d files", bar, m.count, m.total)
+	} else if m.count > 0 {
+		view += MutedStyle.Render(fmt.Sprintf("\n  %d files", m.count))
+	}
+	for _, line := range m.lines {
+		view += "\n" + MutedStyle.Render("  │ "+strings.TrimRight(line, "\r"))
+	}
+	return view
 }
 
 func waitForSpinner(done <-chan error) tea.Cmd {
@@ -70,3 +130,30 @@ func waitForSpinner(done <-chan error) tea.Cmd {
 		return spinnerDoneMsg{err: <-done}
 	}
 }
+
+// PhaseLabel is the spinner message for a generation phase.
+func PhaseLabel(phase string) string {
+	switch phase {
+	case "render":
+		return PhaseRenderLabel
+	case "env":
+		return PhaseEnvLabel
+	case "steps":
+		return PhaseStepsLabel
+	case "git":
+		return PhaseGitLabel
+	case "place":
+		return PhasePlaceLabel
+	}
+	return phase
+}
+
+// PrintStep announces a post-generation step when no spinner is running.
+func PrintStep(command string) {
+	fmt.Println(HintStyle().Render("▶ " + command))
+}
+
+// PrintStepOutput prints a line of a step's output when no spinner is running.
+func PrintStepOutput(line string) {
+	fmt.Println(MutedStyle.Render("  │ " + line))
+}
@@ -0,0 +1,17 @@
+package ui
+
+import "fmt"
+
+func PrintResourceSummary(name, pm string, created []string, updated []string) {
+	fmt.Printf("\n%s\n", SectionTitleStyle().Render(fmt.Sprintf("✅ Resource %s generated", name)))
+	for _, path := range created {
+		fmt.Printf("   %s %s\n", HintStyle().Render("+"), path)
+	}
+	for _, path := range updated {
+		fmt.Printf("   %s %s\n", HintStyle().Render("~"), path)
+	}
+	fmt.Println(HintStyle().Render("Next moves:"))
+	fmt.Printf("   1) run `%s run api:migrate:up` to apply the new migration\n", pm)
+	fmt.Printf("   2) run `%s run openapi:generate` to refresh the API docs\n", pm)
+	fmt.Println("   3) run `go test ./...` in `apps/api` to check the generated handler")
+}
@@ -6,21 +6,49 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func PrintSummary(path string) {
+// Summary describes a generated project for PrintSummary.
+type Summary struct {
+	Path           string
+	PackageManager string
+	// Installed leaves out the install step when --install already ran it.
+	Installed bool
+	// Secrets lists the generated secrets, one "KEY → files" line each.
+	Secrets []string
+	// Staged is set when git staged the files without committing them.
+	Staged   bool
+	Warnings []string
+}
+
+// PrintSummary prints the next moves for a generated project.
+func PrintSummary(summary Summary) {
 	header := SectionTitleStyle().Render("✅ Repo spawned successfully")
-	pathLine := lipgloss.NewStyle().Foreground(lipgloss.Color("51")).Bold(true).Render(path)
+	pathLine := lipgloss.NewStyle().Foreground(lipgloss.Color("51")).Bold(true).Render(summary.Path)
 	steps := []string{
-		"1) `cd` into the project",
-		"2) review generated `.env` files",
-		"3) run the project wide install command `bun install`",
-		"4) run the dev servers with `bun run dev` and read the README for more info",
+		"`cd` into the project",
+		"review generated `.env` files",
+	}
+	if summary.Staged {
+		steps = append(steps, "commit the staged files with `git commit` once your git identity is set")
 	}
+	if !summary.Installed {
+		steps = append(steps, fmt.Sprintf("run the project wide install command `%s install`", summary.PackageManager))
+	}
+	steps = append(steps, fmt.Sprintf("run the dev servers with `%s run dev` and read the README for more info", summary.PackageManager))
 
 	fmt.Printf("\n%s\n", header)
 	fmt.Printf("📦 Destination: %s\n", pathLine)
+	for _, warning := range summary.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	if len(summary.Secrets) > 0 {
+		fmt.Println(HintStyle().Render("Generated secrets (random per project, not tracked by git):"))
+		for _, secret := range summary.Secrets {
+			fmt.Printf("   🔑 %s\n", secret)
+		}
+	}
 	fmt.Println(HintStyle().Render("Next moves:"))
-	for _, step := range steps {
-		fmt.Printf("   %s\n", step)
+	for i, step := range steps {
+		fmt.Printf("   %d) %s\n", i+1, step)
 	}
 	fmt.Println(HintStyle().Render("GG. Build cool things."))
 }
@@ -0,0 +1,29 @@
+package ui
+
+import "fmt"
+
+type ChangeGroup struct {
+	Marker string
+	Label  string
+	Paths  []string
+}
+
+func PrintUpgradeSummary(title string, groups []ChangeGroup, hints []string) {
+	fmt.Printf("\n%s\n", SectionTitleStyle().Render(title))
+	for _, group := range groups {
+		if len(group.Paths) == 0 {
+			continue
+		}
+		fmt.Println(HintStyle().Render(fmt.Sprintf("%s (%d):", group.Label, len(group.Paths))))
+		for _, path := range group.Paths {
+			fmt.Printf("   %s %s\n", HintStyle().Render(group.Marker), path)
+		}
+	}
+	if len(hints) == 0 {
+		return
+	}
+	fmt.Println(HintStyle().Render("Next moves:"))
+	for i, hint := range hints {
+		fmt.Printf("   %d) %s\n", i+1, hint)
+	}
+}
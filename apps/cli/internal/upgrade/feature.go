@@ -0,0 +1,243 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/templatesource"
+)
+
+// Features that AddFeature turns on by changing the project configuration.
+// Opt-in features of the template manifest are accepted as well.
+const (
+	FeatureObservability = "observability"
+	FeatureWeb           = "web"
+	FeatureDocker        = "docker"
+	FeatureS3            = "s3"
+)
+
+var FeatureNames = []string{FeatureObservability, FeatureWeb, FeatureDocker, FeatureS3}
+
+type FeatureOptions struct {
+	// Observability is the stack the observability feature adds. Defaults to
+	// grafana-oss.
+	Observability scaffold.ObservabilityProvider
+	// S3 configures the bucket the s3 feature switches file storage to.
+	S3 *scaffold.S3Config
+}
+
+// AddFeature turns feature on in the project at root. The change is what the
+// feature alters between rendering the project's template with the recorded
+// configuration and with the feature enabled, merged into the working tree
+// like an upgrade. Generated .env files are merged too, so new keys show up
+// next to the values already set.
+func AddFeature(root, feature string, opts FeatureOptions) (Result, error) {
+	result := Result{Root: root}
+	manifest, err := scaffold.ReadManifest(root)
+	if err != nil {
+		return result, err
+	}
+	cfg := scaffold.DefaultConfig()
+	manifest.Config.Apply(&cfg)
+	cfg.Destination = root
+	// The manifest leaves secrets out, so the base render takes them from
+	// the project's .env files.
+	if err := readEnvSecrets(root, &cfg); err != nil {
+		return result, err
+	}
+
+	source, err := templatesource.Resolve(cfg.Template, templatesource.Options{})
+	if err != nil {
+		return result, err
+	}
+	templateHash, err := scaffold.HashTemplate(source.FS)
+	if err != nil {
+		return result, err
+	}
+	// The base render must be what the project was generated from.
+	if templateHash != manifest.TemplateHash {
+		return result, errors.New("the project was generated from another revision of its template; run `gokickstart upgrade` first")
+	}
+
+	target := cfg
+	if err := enableFeature(&target, feature, opts, source); err != nil {
+		return result, err
+	}
+	if err := scaffold.ValidateConfig(target); err != nil {
+		return result, err
+	}
+	if err := fillSecrets(&target); err != nil {
+		return result, err
+	}
+
+	baseFiles, err := renderAll(cfg, source)
+	if err != nil {
+		return result, err
+	}
+	next, err := renderAll(target, source)
+	if err != nil {
+		return result, err
+	}
+	// Files are untouched when they match the manifest; the base render only
+	// serves as the merge base. The manifest does not track the generated
+	// .env files, so they are compared with the base render instead.
+	recorded := map[string]string{}
+	maps.Copy(recorded, manifest.Files)
+	for path, sum := range checksums(baseFiles) {
+		if _, tracked := recorded[path]; !tracked {
+			recorded[path] = sum
+		}
+	}
+	result.Changes, err = applyRender(root, recorded, &baseReader{root: root, rendered: baseFiles}, next)
+	if err != nil {
+		return result, err
+	}
+
+	tracked, err := scaffold.RenderProject(target, source.FS)
+	if err != nil {
+		return result, err
+	}
+	manifest.Config = scaffold.RedactConfigFile(scaffold.ConfigFileFrom(target))
+	manifest.Files = checksums(tracked)
+	if err := scaffold.WriteManifest(root, manifest); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func enableFeature(cfg *scaffold.ScaffoldConfiguration, feature string, opts FeatureOptions, source templatesource.Source) error {
+	already := fmt.Errorf("feature %q is already enabled", feature)
+	switch feature {
+	case FeatureWeb:
+		if cfg.IncludeWeb {
+			return already
+		}
+		cfg.IncludeWeb = true
+	case FeatureDocker:
+		if cfg.IncludeDocker {
+			return already
+		}
+		cfg.IncludeDocker = true
+	case FeatureObservability:
+		provider := opts.Observability
+		if provider == "" {
+			provider = scaffold.ObservabilityGrafanaOSS
+		}
+		if provider == scaffold.ObservabilityNone {
+			return fmt.Errorf("choose an observability stack to add (%s or %s)", scaffold.ObservabilityGrafanaOSS, scaffold.ObservabilityOTLP)
+		}
+		if cfg.Observability == provider {
+			return already
+		}
+		cfg.Observability = provider
+	case FeatureS3:
+		if cfg.Storage.Type == scaffold.StorageS3 {
+			return already
+		}
+		if opts.S3 == nil {
+			return errors.New("the s3 feature needs the endpoint, region, bucket, access key and secret key")
+		}
+		cfg.Storage = scaffold.StorageConfig{Type: scaffold.StorageS3, S3: opts.S3}
+	default:
+		manifest, err := scaffold.LoadTemplateManifest(source.FS)
+		if err != nil {
+			return err
+		}
+		names := slices.Clone(FeatureNames)
+		for _, name := range manifest.FeatureNames() {
+			if manifest.Features[name].When == "" {
+				names = append(names, name)
+			}
+		}
+		if !slices.Contains(names, feature) {
+			return fmt.Errorf("unknown feature %q (available: %s)", feature, strings.Join(names, ", "))
+		}
+		if slices.Contains(cfg.Features, feature) {
+			return already
+		}
+		cfg.Features = append(slices.Clone(cfg.Features), feature)
+	}
+	return nil
+}
+
+// readEnvSecrets sets the database password, S3 keys and generated secrets
+// of cfg to the values in the project's apps/api/.env, falling back to the
+// Compose .env. Empty values and change-me placeholders are left unset.
+func readEnvSecrets(root string, cfg *scaffold.ScaffoldConfiguration) error {
+	values := map[string]string{}
+	for _, path := range []string{".env", "apps/api/.env"} {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, env := scaffold.ParseEnv(data)
+		maps.Copy(values, env)
+	}
+	lookup := func(keys ...string) string {
+		for _, key := range keys {
+			if value := values[key]; value != "" && value != "change-me" {
+				return value
+			}
+		}
+		return ""
+	}
+	if password := lookup("API_DATABASE.PASSWORD", "DB_PASSWORD"); password != "" {
+		cfg.DBConnection.Password = password
+	}
+	if cfg.Storage.S3 != nil {
+		s3 := *cfg.Storage.S3
+		if key := lookup("API_FILE_STORAGE.S3.ACCESS_KEY_ID"); key != "" {
+			s3.AccessKey = key
+		}
+		if key := lookup("API_FILE_STORAGE.S3.SECRET_ACCESS_KEY"); key != "" {
+			s3.SecretKey = key
+		}
+		cfg.Storage.S3 = &s3
+	}
+	cfg.Secrets.AuthSecretKey = lookup("API_AUTH.SECRET_KEY", "API_AUTH_SECRET_KEY")
+	cfg.Secrets.RedisPassword = lookup("API_CACHE.REDIS_PASSWORD", "REDIS_PASSWORD")
+	return nil
+}
+
+// fillSecrets generates the secrets cfg needs but does not have yet, such as
+// the Compose passwords when Docker is added.
+func fillSecrets(cfg *scaffold.ScaffoldConfiguration) error {
+	generated, err := scaffold.GenerateSecrets(*cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.Secrets.AuthSecretKey == "" {
+		cfg.Secrets.AuthSecretKey = generated.AuthSecretKey
+	}
+	if cfg.Secrets.DBPassword == "" {
+		cfg.Secrets.DBPassword = generated.DBPassword
+	}
+	if cfg.Secrets.RedisPassword == "" {
+		cfg.Secrets.RedisPassword = generated.RedisPassword
+	}
+	return nil
+}
+
+// renderAll renders source for cfg in memory, including the generated .env
+// files.
+func renderAll(cfg scaffold.ScaffoldConfiguration, source templatesource.Source) (map[string][]byte, error) {
+	plan, err := scaffold.PlanProject(cfg, source.FS, nil)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(plan.Files))
+	for _, file := range plan.Files {
+		files[file.Path] = file.Content
+	}
+	return files, nil
+}
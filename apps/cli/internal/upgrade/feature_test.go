@@ -0,0 +1,167 @@
+package upgrade
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+)
+
+var featureTemplate = map[string]string{
+	scaffold.TemplateManifestPath: "version: 1\nfeatures:\n  web:\n    when: web\n    files: [apps/web/**]\n  docker:\n    when: docker\n    files: [.env.example]\n",
+	"apps/api/main.go.tmpl":       "package main\n\nfunc main() {\n\tstart()\n{{IF observability != \"none\"}}\n\tstartTracing()\n{{END}}\n}\n",
+	"apps/api/.env.example.tmpl":  "API_PORT=8080\n{{IF observability != \"none\"}}\nAPI_OBSERVABILITY.ENABLED=true\n{{END}}\n",
+	"apps/web/index.html":         "<html></html>\n",
+	".env.example.tmpl":           "DB_PASSWORD=\"postgres\"\nREDIS_PASSWORD=\"change-me\"\nAPI_AUTH_SECRET_KEY=\"change-me\"\n",
+}
+
+func scaffoldFeatureProject(t *testing.T) (root, template string) {
+	t.Helper()
+	template = t.TempDir()
+	for path, content := range featureTemplate {
+		mustWrite(t, template, path, content)
+	}
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.IncludeWeb = false
+	cfg.IncludeDocker = false
+	cfg.InitGit = false
+	cfg.Template = template
+	if err := scaffold.ScaffoldProject(cfg, scaffold.Options{}); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	return cfg.Destination, template
+}
+
+func TestAddFeatureMergesIntoEditedFiles(t *testing.T) {
+	root, _ := scaffoldFeatureProject(t)
+	mustWrite(t, root, "apps/api/main.go", "package main\n\n// Entry point.\nfunc main() {\n\tstart()\n}\n")
+	env := strings.Replace(mustRead(t, root, "apps/api/.env"), `API_PRIMARY.APP_NAME="demo"`, `API_PRIMARY.APP_NAME="Demo API"`, 1)
+	mustWrite(t, root, "apps/api/.env", env)
+
+	result, err := AddFeature(root, FeatureObservability, FeatureOptions{})
+	if err != nil {
+		t.Fatalf("add feature: %v", err)
+	}
+	want := []Change{
+		{Path: "apps/api/.env", Action: ActionMerged},
+		{Path: "apps/api/.env.example", Action: ActionUpdated},
+		{Path: "apps/api/main.go", Action: ActionMerged},
+	}
+	if !reflect.DeepEqual(result.Changes, want) {
+		t.Fatalf("unexpected changes: %+v", result.Changes)
+	}
+	if got := mustRead(t, root, "apps/api/main.go"); got != "package main\n\n// Entry point.\nfunc main() {\n\tstart()\n\tstartTracing()\n}\n" {
+		t.Fatalf("unexpected main.go:\n%s", got)
+	}
+	env = mustRead(t, root, "apps/api/.env")
+	if !strings.Contains(env, "API_OBSERVABILITY.ENABLED=true\n") || !strings.Contains(env, `API_PRIMARY.APP_NAME="Demo API"`) {
+		t.Fatalf("expected the new key and the edited value:\n%s", env)
+	}
+
+	manifest, err := scaffold.ReadManifest(root)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if manifest.Config.Observability != string(scaffold.ObservabilityGrafanaOSS) {
+		t.Fatalf("expected the manifest to record the feature, got %q", manifest.Config.Observability)
+	}
+	if _, tracked := manifest.Files["apps/api/.env"]; tracked {
+		t.Fatalf("did not expect the generated .env to be tracked")
+	}
+
+	if _, err := AddFeature(root, FeatureObservability, FeatureOptions{}); err == nil || !strings.Contains(err.Error(), "already enabled") {
+		t.Fatalf("expected already enabled error, got %v", err)
+	}
+}
+
+func TestAddFeatureDockerSharesSecretsWithTheAPI(t *testing.T) {
+	root, _ := scaffoldFeatureProject(t)
+	env := strings.Replace(mustRead(t, root, "apps/api/.env"), "API_PORT=8080\n", "API_PORT=8080\nAPI_AUTH.SECRET_KEY=\"kept\"\n", 1)
+	mustWrite(t, root, "apps/api/.env", env)
+
+	if _, err := AddFeature(root, FeatureDocker, FeatureOptions{}); err != nil {
+		t.Fatalf("add feature: %v", err)
+	}
+	_, compose := scaffold.ParseEnv([]byte(mustRead(t, root, ".env")))
+	_, api := scaffold.ParseEnv([]byte(mustRead(t, root, "apps/api/.env")))
+	for composeKey, apiKey := range map[string]string{
+		"DB_PASSWORD":         "API_DATABASE.PASSWORD",
+		"REDIS_PASSWORD":      "API_CACHE.REDIS_PASSWORD",
+		"API_AUTH_SECRET_KEY": "API_AUTH.SECRET_KEY",
+	} {
+		value := compose[composeKey]
+		if value == "" || value == "change-me" || value == "postgres" || strings.Contains(value, "redacted") {
+			t.Fatalf("expected a generated %s, got %q", composeKey, value)
+		}
+		if api[apiKey] != value {
+			t.Fatalf("expected %s %q to match %s %q", apiKey, api[apiKey], composeKey, value)
+		}
+	}
+	if api["API_AUTH.SECRET_KEY"] != "kept" {
+		t.Fatalf("expected the existing auth key to be kept, got %q", api["API_AUTH.SECRET_KEY"])
+	}
+}
+
+func TestAddFeatureLeavesUntouchedFilesToTheTemplate(t *testing.T) {
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.IncludeWeb = false
+	cfg.InitGit = false
+	if err := scaffold.ScaffoldProject(cfg, scaffold.Options{}); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+
+	result, err := AddFeature(cfg.Destination, FeatureWeb, FeatureOptions{})
+	if err != nil {
+		t.Fatalf("add feature: %v", err)
+	}
+	for _, action := range []Action{ActionMerged, ActionConflict, ActionRejected} {
+		if paths := result.Paths(action); len(paths) > 0 {
+			t.Fatalf("expected no %s files in a fresh project, got %v", action, paths)
+		}
+	}
+	if len(result.Paths(ActionAdded)) == 0 {
+		t.Fatalf("expected the web app to be added, got %+v", result.Changes)
+	}
+}
+
+func TestAddFeatureAddsFilesAndRejectsClashes(t *testing.T) {
+	root, _ := scaffoldFeatureProject(t)
+	mustWrite(t, root, "apps/web/index.html", "<html>mine</html>\n")
+
+	result, err := AddFeature(root, FeatureWeb, FeatureOptions{})
+	if err != nil {
+		t.Fatalf("add feature: %v", err)
+	}
+	if got := result.Paths(ActionRejected); !reflect.DeepEqual(got, []string{"apps/web/index.html"}) {
+		t.Fatalf("expected the existing file to be rejected, got %+v", result.Changes)
+	}
+	if got := mustRead(t, root, "apps/web/index.html"+RejectSuffix); got != "<html></html>\n" {
+		t.Fatalf("unexpected .rej content %q", got)
+	}
+
+	if _, err := AddFeature(root, "cron", FeatureOptions{}); err == nil || !strings.Contains(err.Error(), `unknown feature "cron"`) {
+		t.Fatalf("expected unknown feature error, got %v", err)
+	}
+	if _, err := AddFeature(root, FeatureS3, FeatureOptions{}); err == nil || !strings.Contains(err.Error(), "needs the endpoint") {
+		t.Fatalf("expected missing s3 settings error, got %v", err)
+	}
+}
+
+func TestAddFeatureRequiresTheGeneratingTemplate(t *testing.T) {
+	root, template := scaffoldFeatureProject(t)
+	if err := os.WriteFile(filepath.Join(template, "apps/web/index.html"), []byte("<html>v2</html>\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if _, err := AddFeature(root, FeatureWeb, FeatureOptions{}); err == nil || !strings.Contains(err.Error(), "gokickstart upgrade") {
+		t.Fatalf("expected upgrade hint, got %v", err)
+	}
+}
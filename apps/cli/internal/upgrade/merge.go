@@ -0,0 +1,58 @@
+package upgrade
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// mergeFile performs a three-way merge of the template change (base -> next)
+// into current using `git merge-file`. Conflicting hunks are returned wrapped
+// in conflict markers and reported through conflicted.
+func mergeFile(current, base, next []byte) (merged []byte, conflicted bool, err error) {
+	dir, err := os.MkdirTemp("", "gokickstart-merge-")
+	if err != nil {
+		return nil, false, err
+	}
+	defer os.RemoveAll(dir)
+
+	inputs := []struct {
+		name    string
+		content []byte
+	}{{"current", current}, {"base", base}, {"next", next}}
+	paths := make([]string, len(inputs))
+	for i, input := range inputs {
+		paths[i] = filepath.Join(dir, input.name)
+		if err := os.WriteFile(paths[i], input.content, 0o600); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "merge-file", "-p",
+		"-L", "yours", "-L", "original template", "-L", "new template",
+		paths[0], paths[1], paths[2])
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	// git merge-file exits with the number of conflicts (capped at 127) and
+	// with a negative status (255) on failure.
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return stdout.Bytes(), false, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128:
+		return stdout.Bytes(), true, nil
+	case stderr.Len() > 0:
+		return nil, false, errors.New(stderr.String())
+	default:
+		return nil, false, err
+	}
+}
+
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0
+}
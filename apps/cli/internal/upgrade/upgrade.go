@@ -0,0 +1,300 @@
+package upgrade
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/templatesource"
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/version"
+)
+
+type Action string
+
+const (
+	// ActionUpdated: the file was untouched locally and took the new template version.
+	ActionUpdated Action = "updated"
+	// ActionAdded: the file is new in the template.
+	ActionAdded Action = "added"
+	// ActionRemoved: the file was untouched locally and is gone from the template.
+	ActionRemoved Action = "removed"
+	// ActionMerged: local edits and template changes merged cleanly.
+	ActionMerged Action = "merged"
+	// ActionConflict: the merge left conflict markers in the file.
+	ActionConflict Action = "conflict"
+	// ActionRejected: the change could not be merged and was written to <path>.rej.
+	ActionRejected Action = "rejected"
+	// ActionKept: the template removed the file but it has local edits.
+	ActionKept Action = "kept"
+	// ActionSkipped: the file was deleted locally, so the template change is ignored.
+	ActionSkipped Action = "skipped"
+)
+
+const RejectSuffix = ".rej"
+
+type Change struct {
+	Path   string
+	Action Action
+}
+
+type Result struct {
+	Root         string
+	FromVersion  string
+	ToVersion    string
+	FromTemplate string
+	ToTemplate   string
+	Changes      []Change
+}
+
+func (r Result) UpToDate() bool {
+	return r.FromTemplate == r.ToTemplate
+}
+
+func (r Result) Paths(action Action) []string {
+	var paths []string
+	for _, change := range r.Changes {
+		if change.Action == action {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths
+}
+
+type Options struct {
+	// Template is the template to upgrade to. Defaults to the template source
+	// recorded in the manifest.
+	Template fs.FS
+	// TemplateSpec is the source spec of Template, recorded in the manifest.
+	TemplateSpec string
+	// BaseTemplate is the template the project was generated from. When nil,
+	// the original files are read back from the git commit that last wrote the
+	// manifest.
+	BaseTemplate fs.FS
+}
+
+// Upgrade re-renders the template with the project's recorded configuration
+// and merges the template changes into the working tree at root.
+func Upgrade(root string, opts Options) (Result, error) {
+	manifest, err := scaffold.ReadManifest(root)
+	if err != nil {
+		return Result{}, err
+	}
+
+	cfg := scaffold.DefaultConfig()
+	manifest.Config.Apply(&cfg)
+	cfg.Destination = root
+
+	source := opts.Template
+	if source == nil {
+		resolved, err := templatesource.Resolve(cfg.Template, templatesource.Options{})
+		if err != nil {
+			return Result{}, err
+		}
+		source = resolved.FS
+	} else {
+		cfg.Template = opts.TemplateSpec
+		manifest.Config.Template = opts.TemplateSpec
+	}
+	templateHash, err := scaffold.HashTemplate(source)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{
+		Root:         root,
+		FromVersion:  manifest.GeneratorVersion,
+		ToVersion:    version.Version,
+		FromTemplate: manifest.TemplateHash,
+		ToTemplate:   templateHash,
+	}
+	if result.UpToDate() {
+		return result, nil
+	}
+
+	next, err := scaffold.RenderProject(cfg, source)
+	if err != nil {
+		return result, err
+	}
+	base, err := newBaseReader(root, cfg, manifest, opts.BaseTemplate)
+	if err != nil {
+		return result, err
+	}
+
+	result.Changes, err = applyRender(root, manifest.Files, base, next)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	manifest.GeneratorVersion = version.Version
+	manifest.TemplateHash = templateHash
+	manifest.UpgradedAt = &now
+	manifest.Files = checksums(next)
+	if err := scaffold.WriteManifest(root, manifest); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// applyRender merges the change from the base render to next into the
+// working tree at root. recorded holds the checksums of the base files.
+func applyRender(root string, recorded map[string]string, base *baseReader, next map[string][]byte) ([]Change, error) {
+	paths := map[string]bool{}
+	for path := range next {
+		paths[path] = true
+	}
+	for path := range recorded {
+		paths[path] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	var changes []Change
+	for _, path := range sorted {
+		content, inNext := next[path]
+		action, err := upgradeFile(root, path, recorded[path], base, content, inNext)
+		if err != nil {
+			return changes, fmt.Errorf("%s: %w", path, err)
+		}
+		if action != "" {
+			changes = append(changes, Change{Path: path, Action: action})
+		}
+	}
+	return changes, nil
+}
+
+func checksums(files map[string][]byte) map[string]string {
+	sums := make(map[string]string, len(files))
+	for path, content := range files {
+		sums[path] = scaffold.Checksum(content)
+	}
+	return sums
+}
+
+// upgradeFile applies the template change for a single path and reports what
+// happened. An empty action means there was nothing to do.
+func upgradeFile(root, path, recorded string, base *baseReader, next []byte, inNext bool) (Action, error) {
+	target := filepath.Join(root, filepath.FromSlash(path))
+	current, err := os.ReadFile(target)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	untouched := exists && recorded != "" && scaffold.Checksum(current) == recorded
+
+	switch {
+	case !inNext:
+		if !exists {
+			return "", nil
+		}
+		if untouched {
+			return ActionRemoved, os.Remove(target)
+		}
+		return ActionKept, nil
+	case exists && bytes.Equal(current, next):
+		return "", nil
+	case recorded == "":
+		if !exists {
+			return ActionAdded, writeFile(target, next)
+		}
+		return ActionRejected, writeFile(target+RejectSuffix, next)
+	case !exists:
+		if recorded == scaffold.Checksum(next) {
+			return "", nil
+		}
+		return ActionSkipped, nil
+	case untouched:
+		return ActionUpdated, writeFile(target, next)
+	case recorded == scaffold.Checksum(next):
+		// Only the user changed this file.
+		return "", nil
+	}
+
+	original, ok := base.read(path, recorded)
+	if !ok || isBinary(current) || isBinary(next) {
+		return ActionRejected, writeFile(target+RejectSuffix, next)
+	}
+	merged, conflicted, err := mergeFile(current, original, next)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(target, merged); err != nil {
+		return "", err
+	}
+	if conflicted {
+		return ActionConflict, nil
+	}
+	return ActionMerged, nil
+}
+
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+// baseReader recovers the originally generated content of a file, which is
+// the merge base. Content is only trusted when it matches the checksum
+// recorded in the manifest.
+type baseReader struct {
+	root     string
+	rendered map[string][]byte
+	commit   string
+}
+
+func newBaseReader(root string, cfg scaffold.ScaffoldConfiguration, manifest scaffold.Manifest, template fs.FS) (*baseReader, error) {
+	reader := &baseReader{root: root}
+	if template != nil {
+		rendered, err := scaffold.RenderProject(cfg, template)
+		if err != nil {
+			return nil, fmt.Errorf("render base template: %w", err)
+		}
+		reader.rendered = rendered
+		return reader, nil
+	}
+	reader.commit = manifestCommit(root)
+	return reader, nil
+}
+
+func (b *baseReader) read(path, checksum string) ([]byte, bool) {
+	var content []byte
+	switch {
+	case b.rendered != nil:
+		content = b.rendered[path]
+	case b.commit != "":
+		cmd := exec.Command("git", "show", b.commit+":"+path)
+		cmd.Dir = b.root
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, false
+		}
+		content = out
+	}
+	if content == nil || scaffold.Checksum(content) != checksum {
+		return nil, false
+	}
+	return content, true
+}
+
+// manifestCommit returns the last commit that wrote the manifest, i.e. the
+// commit holding the files as they were generated, or "" outside git.
+func manifestCommit(root string) string {
+	manifestPath := filepath.ToSlash(filepath.Join(scaffold.ManifestDir, scaffold.ManifestFile))
+	cmd := exec.Command("git", "log", "-1", "--format=%H", "--", manifestPath)
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return string(bytes.TrimSpace(out))
+}
@@ -0,0 +1,180 @@
+package upgrade
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jeheskielSunloy77/go-kickstart/apps/cli/internal/scaffold"
+)
+
+func baseTemplate() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md.tmpl": {Data: []byte("# {{PROJECT_NAME}}\n\nintro\n")},
+		"untouched.txt":  {Data: []byte("v1\n")},
+		"edited.txt":     {Data: []byte("line 1\nline 2\nline 3\nline 4\nline 5\n")},
+		"clash.txt":      {Data: []byte("value = 1\n")},
+		"dropped.txt":    {Data: []byte("old\n")},
+		"deleted.txt":    {Data: []byte("v1\n")},
+		"local-only.txt": {Data: []byte("same\n")},
+	}
+}
+
+func nextTemplate() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md.tmpl": {Data: []byte("# {{PROJECT_NAME}}\n\nintro\n")},
+		"untouched.txt":  {Data: []byte("v2\n")},
+		"edited.txt":     {Data: []byte("line 1\nline 2\nline 3\nline 4\nline five\n")},
+		"clash.txt":      {Data: []byte("value = 2\n")},
+		"deleted.txt":    {Data: []byte("v2\n")},
+		"local-only.txt": {Data: []byte("same\n")},
+		"new/added.txt":  {Data: []byte("fresh\n")},
+	}
+}
+
+func scaffoldProject(t *testing.T, initGit bool) string {
+	t.Helper()
+	cfg := scaffold.DefaultConfig()
+	cfg.ProjectName = "demo"
+	cfg.ModulePath = "github.com/acme/demo"
+	cfg.Destination = t.TempDir()
+	cfg.InitGit = initGit
+	if err := scaffold.ScaffoldFromFS(cfg, scaffold.Options{}, baseTemplate(), nil); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+
+	root := cfg.Destination
+	mustWrite(t, root, "edited.txt", "line ONE\nline 2\nline 3\nline 4\nline 5\n")
+	mustWrite(t, root, "clash.txt", "value = 3\n")
+	mustWrite(t, root, "local-only.txt", "mine\n")
+	if err := os.Remove(filepath.Join(root, "deleted.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	return root
+}
+
+func mustWrite(t *testing.T, root, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func mustRead(t *testing.T, root, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, path))
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestUpgradeWithBaseTemplate(t *testing.T) {
+	root := scaffoldProject(t, false)
+
+	result, err := Upgrade(root, Options{Template: nextTemplate(), BaseTemplate: baseTemplate()})
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+
+	want := []Change{
+		{Path: "clash.txt", Action: ActionConflict},
+		{Path: "deleted.txt", Action: ActionSkipped},
+		{Path: "dropped.txt", Action: ActionRemoved},
+		{Path: "edited.txt", Action: ActionMerged},
+		{Path: "new/added.txt", Action: ActionAdded},
+		{Path: "untouched.txt", Action: ActionUpdated},
+	}
+	if !reflect.DeepEqual(result.Changes, want) {
+		t.Fatalf("unexpected changes:\n got %v\nwant %v", result.Changes, want)
+	}
+
+	if got := mustRead(t, root, "untouched.txt"); got != "v2\n" {
+		t.Fatalf("expected untouched file to be updated, got %q", got)
+	}
+	if got := mustRead(t, root, "edited.txt"); got != "line ONE\nline 2\nline 3\nline 4\nline five\n" {
+		t.Fatalf("expected both edits to be merged, got %q", got)
+	}
+	clash := mustRead(t, root, "clash.txt")
+	if !strings.Contains(clash, "<<<<<<< yours") || !strings.Contains(clash, ">>>>>>> new template") {
+		t.Fatalf("expected conflict markers, got %q", clash)
+	}
+	if got := mustRead(t, root, "local-only.txt"); got != "mine\n" {
+		t.Fatalf("expected local-only edit to be kept, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "dropped.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected dropped.txt to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "deleted.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected locally deleted file to stay deleted")
+	}
+
+	manifest, err := scaffold.ReadManifest(root)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if manifest.TemplateHash != result.ToTemplate || manifest.UpgradedAt == nil {
+		t.Fatalf("expected manifest to record the new template")
+	}
+	if _, ok := manifest.Files["dropped.txt"]; ok {
+		t.Fatalf("expected removed file to leave the manifest")
+	}
+
+	again, err := Upgrade(root, Options{Template: nextTemplate()})
+	if err != nil {
+		t.Fatalf("second upgrade: %v", err)
+	}
+	if !again.UpToDate() {
+		t.Fatalf("expected second upgrade to be a no-op")
+	}
+}
+
+func TestUpgradeWithoutBaseWritesRejects(t *testing.T) {
+	root := scaffoldProject(t, false)
+
+	result, err := Upgrade(root, Options{Template: nextTemplate()})
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	rejected := result.Paths(ActionRejected)
+	if !reflect.DeepEqual(rejected, []string{"clash.txt", "edited.txt"}) {
+		t.Fatalf("expected edited files to be rejected without a merge base, got %v", rejected)
+	}
+	if got := mustRead(t, root, "edited.txt.rej"); got != "line 1\nline 2\nline 3\nline 4\nline five\n" {
+		t.Fatalf("expected .rej to hold the new template version, got %q", got)
+	}
+	if got := mustRead(t, root, "clash.txt"); got != "value = 3\n" {
+		t.Fatalf("expected local file to be left alone, got %q", got)
+	}
+}
+
+func TestUpgradeReadsBaseFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "gokickstart")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "gokickstart@example.com")
+	}
+	root := scaffoldProject(t, true)
+
+	result, err := Upgrade(root, Options{Template: nextTemplate()})
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if got := result.Paths(ActionMerged); !reflect.DeepEqual(got, []string{"edited.txt"}) {
+		t.Fatalf("expected edited.txt to merge using the committed base, got %v", result.Changes)
+	}
+	if got := result.Paths(ActionConflict); !reflect.DeepEqual(got, []string{"clash.txt"}) {
+		t.Fatalf("expected clash.txt to conflict, got %v", result.Changes)
+	}
+}
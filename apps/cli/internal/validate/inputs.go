@@ -2,8 +2,11 @@ package validate
 
 import (
 	"errors"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 var modulePathRe = regexp.MustCompile(`^[a-zA-Z0-9_.-]+(/[a-zA-Z0-9_.-]+)+$`)
@@ -27,3 +30,53 @@ func ModulePath(path string) error {
 	}
 	return nil
 }
+
+func Port(port string) error {
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return errors.New("port must be a number between 1 and 65535")
+	}
+	return nil
+}
+
+// GitBranch checks name against the rules of `git check-ref-format --branch`.
+func GitBranch(name string) error {
+	invalid := name == "" || name == "@" ||
+		strings.HasPrefix(name, "-") || strings.HasPrefix(name, "/") ||
+		strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") || strings.HasSuffix(name, ".lock") ||
+		strings.Contains(name, "..") || strings.Contains(name, "//") || strings.Contains(name, "@{") ||
+		strings.ContainsAny(name, " ~^:?*[\\\t\r\n\x7f")
+	if invalid {
+		return errors.New("branch name is not a valid git branch name")
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return errors.New("branch name is not a valid git branch name")
+		}
+	}
+	return nil
+}
+
+func Email(email string) error {
+	at := strings.Index(email, "@")
+	if at < 1 || at == len(email)-1 || strings.ContainsAny(email, " <>\t\r\n") {
+		return errors.New("email must look like name@example.com")
+	}
+	return nil
+}
+
+// MetadataText checks a project description or author, which end up in
+// quoted strings in package.json, Dockerfiles and .env files.
+func MetadataText(value string) error {
+	if strings.ContainsAny(value, "\"\\`$") || strings.ContainsFunc(value, unicode.IsControl) {
+		return errors.New("must be a single line without quotes, backslashes, backticks or $")
+	}
+	return nil
+}
+
+func Homepage(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("homepage must be an http:// or https:// URL")
+	}
+	return MetadataText(value)
+}
@@ -50,3 +50,103 @@ func TestModulePath(t *testing.T) {
 		})
 	}
 }
+
+func TestPort(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", "", true},
+		{"ok", "8080", false},
+		{"zero", "0", true},
+		{"too big", "65536", true},
+		{"not a number", "http", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := Port(c.input)
+			if c.wantErr && err == nil {
+				t.Fatalf("expected error")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGitBranch(t *testing.T) {
+	for _, ok := range []string{"main", "trunk", "feature/init", "release-1.0"} {
+		if err := GitBranch(ok); err != nil {
+			t.Fatalf("GitBranch(%q): unexpected error: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "-main", "my branch", "a..b", "main.lock", "feat/", "x/.hidden", "a:b"} {
+		if err := GitBranch(bad); err == nil {
+			t.Fatalf("GitBranch(%q): expected error", bad)
+		}
+	}
+}
+
+func TestEmail(t *testing.T) {
+	for _, ok := range []string{"dev@example.com", "ci@localhost"} {
+		if err := Email(ok); err != nil {
+			t.Fatalf("Email(%q): unexpected error: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "dev", "@example.com", "dev@", "Dev <dev@example.com>"} {
+		if err := Email(bad); err == nil {
+			t.Fatalf("Email(%q): expected error", bad)
+		}
+	}
+}
+
+func TestMetadataText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"author", "Jane Doe <jane@example.com>", false},
+		{"quote", `The "best" app`, true},
+		{"dollar", "costs $5", true},
+		{"newline", "two\nlines", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := MetadataText(c.input)
+			if c.wantErr && err == nil {
+				t.Fatalf("expected error")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHomepage(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"https", "https://example.com/app", false},
+		{"no scheme", "example.com", true},
+		{"ftp", "ftp://example.com", true},
+		{"quote", `https://example.com/"`, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := Homepage(c.input)
+			if c.wantErr && err == nil {
+				t.Fatalf("expected error")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package version
+
+// Version is the gokickstart release. It is recorded in the manifest of every
+// generated project.
+const Version = "0.1.0"